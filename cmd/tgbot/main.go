@@ -4,19 +4,25 @@ import (
 	"context"
 	"github.com/jmoiron/sqlx"
 	"github.com/joho/godotenv"
+	amqp "github.com/rabbitmq/amqp091-go"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync"
 	"syscall"
+	"time"
+	_ "time/tzdata" // база часовых поясов встроена в бинарник: в slim-образе её может не быть
 
 	pstgre "tg-bot/internal/adapters/db"
 	"tg-bot/internal/adapters/rabbitmq"
 	"tg-bot/internal/adapters/telegram"
+	"tg-bot/internal/app"
 	"tg-bot/internal/handler"
+	"tg-bot/internal/models"
 	"tg-bot/internal/repository"
 	"tg-bot/internal/service"
 )
@@ -29,21 +35,33 @@ func main() {
 	if err := loadConfig(); err != nil {
 		logrus.Fatal("error initializing configs", err)
 	}
+	mustInitTimezone()
 	db := mustInitDB()
 	rmq := mustInitRabbitMQ()
 	repos := repository.NewRepository(db)
-	services := service.NewService(repos, rmq)
 	botAdapter := mustInitBot()
-	handlers := handler.NewHandlers(botAdapter.Tg, services)
+	notifier := app.NewTelegramNotifier(botAdapter.Tg)
+	services := service.NewService(repos, rmq, notifier, retryPolicy())
+	consumers := mustDeclareConsumers(rmq, services)
+	states := mustInitStateStore(db)
+	handlers := handler.NewHandlers(botAdapter.Tg, services, states, adminChatIDs())
 
 	var wg sync.WaitGroup
+	for _, c := range consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			startConsumer(ctx, rmq, services, c)
+		}()
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		startConsumer(ctx, rmq, services)
+		startOutboxRelay(ctx, services)
 	}()
 
-	startCron(services)
+	startCron(ctx, services, states)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -66,6 +84,15 @@ func loadConfig() error {
 	return godotenv.Load(".env")
 }
 
+// Часовой пояс по умолчанию для пользователей, которые не выбрали свой
+func mustInitTimezone() {
+	loc, err := time.LoadLocation(viper.GetString("timezone.default"))
+	if err != nil {
+		logrus.Fatalf("invalid timezone.default: %s", err)
+	}
+	models.DefaultLocation = loc
+}
+
 // Инициализация БД
 func mustInitDB() *sqlx.DB {
 	db, err := pstgre.NewPostgresDB(
@@ -98,12 +125,29 @@ func mustInitRabbitMQ() *rabbitmq.RabbitMQ {
 	if err != nil {
 		logrus.Fatalf("RabbitMQ connect error: %s", err)
 	}
-	if _, err := rmq.DeclareQueue("user.events"); err != nil {
-		logrus.Fatalf("queue declare error: %s", err)
+	if err := rmq.DeclareExchange(models.EventsExchange, amqp.ExchangeTopic); err != nil {
+		logrus.Fatalf("exchange declare error: %s", err)
 	}
 	return rmq
 }
 
+// Инициализация хранилища состояний диалогов: postgres (по умолчанию) или memory
+func mustInitStateStore(db *sqlx.DB) repository.StateStore {
+	ttl, err := time.ParseDuration(viper.GetString("states.ttl"))
+	if err != nil || ttl <= 0 {
+		logrus.Fatalf("invalid states.ttl %q: %v", viper.GetString("states.ttl"), err)
+	}
+	switch driver := viper.GetString("states.driver"); driver {
+	case "postgres":
+		return repository.NewStatePostgres(db, ttl)
+	case "memory":
+		return repository.NewStateMemory(ttl)
+	default:
+		logrus.Fatalf("unknown states.driver %q", driver)
+		return nil
+	}
+}
+
 // Инициализация Telegram Bot
 func mustInitBot() *telegram.BotAdapter {
 	botAdapter, err := telegram.NewBot(os.Getenv("TOKEN_BOT"))
@@ -114,54 +158,262 @@ func mustInitBot() *telegram.BotAdapter {
 }
 
 // Запуск Cron-задач
-func startCron(services *service.Service) {
+func startCron(ctx context.Context, services *service.Service, states repository.StateStore) {
 	c := cron.New(cron.WithLogger(cron.VerbosePrintfLogger(log.New(os.Stdout, "cron: ", log.LstdFlags))))
-	_, err := c.AddFunc("0 10 * * *", func() {
+	_, err := c.AddFunc(viper.GetString("cron.close_events"), func() {
 		logrus.Info("cron: running CheckAndUpdateEvents")
-		// services.Events.CheckAndUpdateEvents() // пример вызова бизнес-логики
+		if err := services.Events.CheckAndUpdateEvents(); err != nil {
+			logrus.Errorf("cron: CheckAndUpdateEvents failed: %v", err)
+		}
+	})
+	if err != nil {
+		logrus.Fatalf("cron add error: %v", err)
+	}
+
+	_, err = c.AddFunc("@hourly", func() {
+		if err := states.DeleteExpired(); err != nil {
+			logrus.Errorf("cron: failed to delete expired states: %v", err)
+		}
+	})
+	if err != nil {
+		logrus.Fatalf("cron add error: %v", err)
+	}
+
+	offsets := reminderOffsets()
+	_, err = c.AddFunc(viper.GetString("reminders.schedule"), func() {
+		if err := services.Reminders.SendDueReminders(offsets); err != nil {
+			logrus.Errorf("cron: SendDueReminders failed: %v", err)
+		}
+	})
+	if err != nil {
+		logrus.Fatalf("cron add error: %v", err)
+	}
+
+	retention, err := time.ParseDuration(viper.GetString("outbox.retention"))
+	if err != nil || retention <= 0 {
+		logrus.Fatalf("invalid outbox.retention %q: %v", viper.GetString("outbox.retention"), err)
+	}
+	_, err = c.AddFunc("@daily", func() {
+		_ = services.Outbox.Cleanup(retention)
+	})
+	if err != nil {
+		logrus.Fatalf("cron add error: %v", err)
+	}
+
+	_, err = c.AddFunc(viper.GetString("digest.schedule"), func() {
+		if err := services.Events.SendJoinDigests(); err != nil {
+			logrus.Errorf("cron: SendJoinDigests failed: %v", err)
+		}
 	})
 	if err != nil {
 		logrus.Fatalf("cron add error: %v", err)
 	}
 	c.Start()
 	go func() {
-		<-context.Background().Done()
+		<-ctx.Done()
 		c.Stop()
 	}()
 }
 
-// Консьюмер RabbitMQ
-func startConsumer(ctx context.Context, rmq *rabbitmq.RabbitMQ, services *service.Service) {
-	q, err := rmq.DeclareQueue("user.events")
-	if err != nil {
-		logrus.Fatalf("DeclareQueue in consumer failed: %v", err)
+// startOutboxRelay периодически пересылает накопленные в outbox доменные события в RabbitMQ.
+// Если пачка заполнена целиком, следующая забирается сразу, без ожидания тика.
+func startOutboxRelay(ctx context.Context, services *service.Service) {
+	interval, err := time.ParseDuration(viper.GetString("outbox.interval"))
+	if err != nil || interval <= 0 {
+		logrus.Fatalf("invalid outbox.interval %q: %v", viper.GetString("outbox.interval"), err)
 	}
-	msgs, err := rmq.Consume(q.Name)
+	batch := viper.GetInt("outbox.batch_size")
+	if batch <= 0 {
+		logrus.Fatalf("invalid outbox.batch_size %d", batch)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("outbox: context canceled, stop relay")
+			return
+		case <-ticker.C:
+			for ctx.Err() == nil {
+				sent, err := services.Outbox.Relay(batch)
+				if err != nil || sent < batch {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Политика повторной обработки сообщений консьюмером из конфига
+func retryPolicy() service.RetryPolicy {
+	delay, err := time.ParseDuration(viper.GetString("consumer.retry_delay"))
+	if err != nil || delay <= 0 {
+		logrus.Fatalf("invalid consumer.retry_delay %q: %v", viper.GetString("consumer.retry_delay"), err)
+	}
+	attempts := viper.GetInt("consumer.max_attempts")
+	if attempts <= 0 {
+		logrus.Fatalf("invalid consumer.max_attempts %d", attempts)
+	}
+	return service.RetryPolicy{MaxAttempts: attempts, Delay: delay}
+}
+
+// Chat ID администраторов бота (команды /dlq и /dlq_replay)
+func adminChatIDs() []int64 {
+	var ids []int64
+	for _, raw := range viper.GetStringSlice("admin.chat_ids") {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			logrus.Fatalf("invalid admin chat id %q: %v", raw, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Отступы напоминаний до начала события из конфига (например, "24h", "1h")
+func reminderOffsets() []time.Duration {
+	var offsets []time.Duration
+	for _, raw := range viper.GetStringSlice("reminders.offsets") {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			logrus.Fatalf("invalid reminder offset %q: %v", raw, err)
+		}
+		offsets = append(offsets, d)
+	}
+	return offsets
+}
+
+// consumer — компонент, читающий доменные события из своей очереди
+type consumer struct {
+	name     string
+	queue    string
+	bindings []string // ключи маршрутизации (типы событий), на которые подписана очередь
+	prefetch int
+	workers  int
+	handle   func(body []byte) error
+}
+
+// consumerHandlers — обработчики компонентов-потребителей; настройки каждого в consumers.<name>
+func consumerHandlers(services *service.Service) map[string]func(body []byte) error {
+	return map[string]func(body []byte) error{
+		"stats": services.Stats.HandleEvent,
+	}
+}
+
+// mustDeclareConsumers объявляет очереди компонентов, привязывает их к обменнику событий
+// и готовит для них очереди повтора и DLQ. Вызывается до запуска relay, чтобы первые
+// события не ушли в обменник без привязанных очередей.
+func mustDeclareConsumers(rmq *rabbitmq.RabbitMQ, services *service.Service) []consumer {
+	handlers := consumerHandlers(services)
+	for name := range viper.GetStringMap("consumers") {
+		if _, ok := handlers[name]; !ok {
+			logrus.Fatalf("consumers.%s: no such component", name)
+		}
+	}
+
+	var consumers []consumer
+	for name, handle := range handlers {
+		key := "consumers." + name
+		c := consumer{
+			name:     name,
+			queue:    viper.GetString(key + ".queue"),
+			bindings: viper.GetStringSlice(key + ".bindings"),
+			prefetch: viper.GetInt(key + ".prefetch"),
+			workers:  viper.GetInt(key + ".workers"),
+			handle:   handle,
+		}
+		if c.queue == "" || len(c.bindings) == 0 || c.prefetch < 0 || c.workers <= 0 {
+			logrus.Fatalf("invalid %s config: queue %q, bindings %v, prefetch %d, workers %d",
+				key, c.queue, c.bindings, c.prefetch, c.workers)
+		}
+
+		if _, err := rmq.DeclareQueue(c.queue); err != nil {
+			logrus.Fatalf("queue declare error: %s", err)
+		}
+		for _, binding := range c.bindings {
+			if err := rmq.BindQueue(c.queue, binding, models.EventsExchange); err != nil {
+				logrus.Fatalf("queue bind error: %s", err)
+			}
+		}
+		if err := services.DeadLetters.Setup(c.queue); err != nil {
+			logrus.Fatalf("dead-letter setup error: %s", err)
+		}
+		consumers = append(consumers, c)
+	}
+	return consumers
+}
+
+// Консьюмер RabbitMQ: c.workers горутин обрабатывают сообщения очереди компонента параллельно
+func startConsumer(ctx context.Context, rmq *rabbitmq.RabbitMQ, services *service.Service, c consumer) {
+	msgs, err := rmq.Consume(c.queue, c.prefetch)
 	if err != nil {
 		logrus.Fatalf("Consume error: %s", err)
 	}
+	logrus.Infof("consumer %s: consuming %s (workers %d, prefetch %d)", c.name, c.queue, c.workers, c.prefetch)
+
+	var wg sync.WaitGroup
+	for range c.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consume(ctx, services, c, msgs)
+		}()
+	}
+	wg.Wait()
+}
+
+func consume(ctx context.Context, services *service.Service, c consumer, msgs <-chan amqp.Delivery) {
 	for {
 		select {
 		case <-ctx.Done():
-			logrus.Info("consumer: context canceled, stop consuming")
+			logrus.Infof("consumer %s: context canceled, stop consuming", c.name)
 			return
 		case msg, ok := <-msgs:
 			if !ok {
-				logrus.Warn("consumer: messages channel closed")
+				// Адаптер сам переподписывается после разрыва, канал закрывается только в rmq.Close
+				logrus.Infof("consumer %s: rabbitmq closed, stop consuming", c.name)
 				return
 			}
-			logrus.Infof("Received event: %s", string(msg.Body))
-			if err := services.Stats.HandleEvent(msg.Body); err != nil {
-				logrus.Errorf("failed to handle event: %v", err)
-				_ = msg.Nack(false, true)
-			} else {
+			logrus.Infof("consumer %s: received %s: %s", c.name, msg.RoutingKey, string(msg.Body))
+			err := c.handle(msg.Body)
+			if err == nil {
 				_ = msg.Ack(false)
+				continue
 			}
+			// Ошибка: копия сообщения уходит в очередь повтора или DLQ, оригинал подтверждается
+			if err := services.DeadLetters.Retry(c.queue, msg, err); err != nil {
+				logrus.Errorf("consumer %s: failed to schedule retry, requeue: %v", c.name, err)
+				_ = msg.Nack(false, true)
+				continue
+			}
+			_ = msg.Ack(false)
 		}
 	}
 }
 
 func initConfig() error {
+	viper.SetDefault("cron.close_events", "0 3 * * *")
+	viper.SetDefault("timezone.default", "Asia/Almaty")
+	viper.SetDefault("states.driver", "postgres")
+	viper.SetDefault("states.ttl", "24h")
+	viper.SetDefault("reminders.schedule", "*/5 * * * *")
+	viper.SetDefault("reminders.offsets", []string{"24h", "1h"})
+	viper.SetDefault("digest.schedule", "0 * * * *")
+	viper.SetDefault("outbox.interval", "1s")
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.retention", "168h")
+	viper.SetDefault("consumer.max_attempts", 5)
+	viper.SetDefault("consumer.retry_delay", "30s")
+	viper.SetDefault("consumers.stats.queue", "user.events")
+	viper.SetDefault("consumers.stats.bindings", []string{
+		models.DomainUserCreated,
+		models.DomainEventCreated,
+		models.DomainParticipantRequested,
+		models.DomainParticipantApproved,
+	})
+	viper.SetDefault("consumers.stats.prefetch", 20)
+	viper.SetDefault("consumers.stats.workers", 4)
 	viper.AddConfigPath("configs")
 	viper.SetConfigName("config")
 	return viper.ReadInConfig()
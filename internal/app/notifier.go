@@ -1 +1,146 @@
 package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
+	"github.com/sirupsen/logrus"
+)
+
+// Button — inline-кнопка в уведомлении
+type Button struct {
+	Text         string
+	CallbackData string
+	URL          string
+}
+
+// Message — исходящее уведомление, не зависящее от Telegram-библиотеки
+type Message struct {
+	ChatID    int64
+	Text      string
+	ParseMode string
+	Buttons   [][]Button
+}
+
+// Notifier отправляет уведомления пользователям. Сервисы зависят от него, а не от telego.
+type Notifier interface {
+	// SendMessage — простая отправка текста без ошибки (совместим с handler.Messenger)
+	SendMessage(chatID int64, text string)
+	Send(ctx context.Context, msg Message) error
+	// SendTemplate рендерит шаблон name с данными data и отправляет результат
+	SendTemplate(ctx context.Context, chatID int64, name string, data any, buttons [][]Button) error
+}
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = time.Second
+)
+
+// TelegramNotifier — реализация Notifier поверх telego с повтором при 429/5xx
+type TelegramNotifier struct {
+	bot         *telego.Bot
+	maxAttempts int
+	baseDelay   time.Duration
+}
+
+func NewTelegramNotifier(bot *telego.Bot) *TelegramNotifier {
+	return &TelegramNotifier{
+		bot:         bot,
+		maxAttempts: defaultMaxAttempts,
+		baseDelay:   defaultBaseDelay,
+	}
+}
+
+func (n *TelegramNotifier) SendMessage(chatID int64, text string) {
+	if err := n.Send(context.Background(), Message{ChatID: chatID, Text: text}); err != nil {
+		logrus.Errorf("notifier: %v", err)
+	}
+}
+
+func (n *TelegramNotifier) SendTemplate(ctx context.Context, chatID int64, name string, data any, buttons [][]Button) error {
+	text, err := Render(name, data)
+	if err != nil {
+		return err
+	}
+	return n.Send(ctx, Message{ChatID: chatID, Text: text, Buttons: buttons})
+}
+
+func (n *TelegramNotifier) Send(ctx context.Context, msg Message) error {
+	params := &telego.SendMessageParams{
+		ChatID:    telego.ChatID{ID: msg.ChatID},
+		Text:      msg.Text,
+		ParseMode: msg.ParseMode,
+	}
+	if len(msg.Buttons) > 0 {
+		params.ReplyMarkup = InlineKeyboard(msg.Buttons)
+	}
+
+	var err error
+	for attempt := 1; attempt <= n.maxAttempts; attempt++ {
+		if _, err = n.bot.SendMessage(ctx, params); err == nil {
+			return nil
+		}
+		delay, retry := n.retryDelay(err, attempt)
+		if !retry || attempt == n.maxAttempts {
+			break
+		}
+		logrus.Warnf("notifier: send to chat %d failed (attempt %d), retry in %s: %v", msg.ChatID, attempt, delay, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return fmt.Errorf("notifier: send to chat %d: %w", msg.ChatID, err)
+}
+
+// retryDelay решает, стоит ли повторять запрос: 429 — ждём retry_after, 5xx — экспоненциальная задержка
+func (n *TelegramNotifier) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var apiErr *telegoapi.Error
+	if !errors.As(err, &apiErr) {
+		return 0, false
+	}
+	backoff := n.baseDelay * time.Duration(1<<(attempt-1))
+	switch {
+	case apiErr.ErrorCode == 429:
+		if apiErr.Parameters != nil && apiErr.Parameters.RetryAfter > 0 {
+			return time.Duration(apiErr.Parameters.RetryAfter) * time.Second, true
+		}
+		return backoff, true
+	case apiErr.ErrorCode >= 500:
+		return backoff, true
+	}
+	return 0, false
+}
+
+// InlineKeyboard переводит кнопки уведомления в разметку telego
+func InlineKeyboard(rows [][]Button) *telego.InlineKeyboardMarkup {
+	keyboard := make([][]telego.InlineKeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		buttons := make([]telego.InlineKeyboardButton, 0, len(row))
+		for _, b := range row {
+			buttons = append(buttons, telego.InlineKeyboardButton{Text: b.Text, CallbackData: b.CallbackData, URL: b.URL})
+		}
+		keyboard = append(keyboard, buttons)
+	}
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: keyboard}
+}
+
+// Render рендерит именованный шаблон уведомления
+func Render(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("notifier: render template %q: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+var (
+	_ Notifier = (*TelegramNotifier)(nil)
+	_ Notifier = (*FakeNotifier)(nil)
+)
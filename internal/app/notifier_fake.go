@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeNotifier — in-memory реализация Notifier для тестов: сохраняет отправленные сообщения
+type FakeNotifier struct {
+	mu       sync.Mutex
+	messages []Message
+	// Err, если задан, возвращается из Send вместо сохранения сообщения
+	Err error
+}
+
+func NewFakeNotifier() *FakeNotifier {
+	return &FakeNotifier{}
+}
+
+func (f *FakeNotifier) SendMessage(chatID int64, text string) {
+	_ = f.Send(context.Background(), Message{ChatID: chatID, Text: text})
+}
+
+func (f *FakeNotifier) SendTemplate(ctx context.Context, chatID int64, name string, data any, buttons [][]Button) error {
+	text, err := Render(name, data)
+	if err != nil {
+		return err
+	}
+	return f.Send(ctx, Message{ChatID: chatID, Text: text, Buttons: buttons})
+}
+
+func (f *FakeNotifier) Send(_ context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.messages = append(f.messages, msg)
+	return nil
+}
+
+// Messages возвращает копию всех отправленных сообщений
+func (f *FakeNotifier) Messages() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.messages...)
+}
+
+// MessagesTo возвращает сообщения, отправленные в конкретный чат
+func (f *FakeNotifier) MessagesTo(chatID int64) []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []Message
+	for _, m := range f.messages {
+		if m.ChatID == chatID {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Reset очищает сохранённые сообщения
+func (f *FakeNotifier) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = nil
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/mymmrac/telego/telegoapi"
+)
+
+// callerStub отвечает на запросы к Bot API заранее заданными ответами по порядку
+type callerStub struct {
+	mu        sync.Mutex
+	responses []*telegoapi.Response
+	calls     int
+}
+
+func (c *callerStub) Call(_ context.Context, _ string, _ *telegoapi.RequestData) (*telegoapi.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := c.responses[min(c.calls, len(c.responses)-1)]
+	c.calls++
+	return resp, nil
+}
+
+var okResponse = &telegoapi.Response{
+	Ok:     true,
+	Result: []byte(`{"message_id":1,"date":0,"chat":{"id":1,"type":"private"}}`),
+}
+
+func apiError(code, retryAfter int) *telegoapi.Response {
+	resp := &telegoapi.Response{Error: &telegoapi.Error{ErrorCode: code, Description: "stub"}}
+	if retryAfter > 0 {
+		resp.Parameters = &telegoapi.ResponseParameters{RetryAfter: retryAfter}
+	}
+	return resp
+}
+
+func newTestNotifier(t *testing.T, caller *callerStub) *TelegramNotifier {
+	t.Helper()
+	bot, err := telego.NewBot("123456:"+strings.Repeat("a", 35), telego.WithAPICaller(caller), telego.WithDiscardLogger())
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	n := NewTelegramNotifier(bot)
+	n.baseDelay = time.Millisecond
+	return n
+}
+
+func TestTelegramNotifierRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []*telegoapi.Response
+		wantCalls int
+		wantErr   bool
+	}{
+		{"ok", []*telegoapi.Response{okResponse}, 1, false},
+		{"429 then ok", []*telegoapi.Response{apiError(429, 0), okResponse}, 2, false},
+		{"5xx then ok", []*telegoapi.Response{apiError(502, 0), apiError(500, 0), okResponse}, 3, false},
+		{"5xx until attempts run out", []*telegoapi.Response{apiError(500, 0)}, defaultMaxAttempts, true},
+		{"4xx is not retried", []*telegoapi.Response{apiError(403, 0), okResponse}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caller := &callerStub{responses: tt.responses}
+			err := newTestNotifier(t, caller).Send(context.Background(), Message{ChatID: 1, Text: "hi"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if caller.calls != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", caller.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTelegramNotifierRetryHonoursContext(t *testing.T) {
+	// retry_after в секундах — за это время контекст успеет отмениться
+	caller := &callerStub{responses: []*telegoapi.Response{apiError(429, 30), okResponse}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := newTestNotifier(t, caller).Send(ctx, Message{ChatID: 1, Text: "hi"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context deadline, got %v", err)
+	}
+	if caller.calls != 1 {
+		t.Fatalf("calls = %d, want 1", caller.calls)
+	}
+}
+
+func TestFakeNotifier(t *testing.T) {
+	fake := NewFakeNotifier()
+	data := map[string]any{"Event": map[string]any{"ID": 42, "Title": "Кино"}}
+	buttons := [][]Button{{{Text: "ok", CallbackData: "x"}}}
+	if err := fake.SendTemplate(context.Background(), 7, TemplateRequestApproved, data, buttons); err != nil {
+		t.Fatalf("SendTemplate: %v", err)
+	}
+	fake.SendMessage(8, "hello")
+
+	got := fake.MessagesTo(7)
+	if len(got) != 1 || !strings.Contains(got[0].Text, "«Кино»") || len(got[0].Buttons) != 1 {
+		t.Fatalf("MessagesTo(7) = %+v", got)
+	}
+	if len(fake.Messages()) != 2 {
+		t.Fatalf("Messages() = %+v", fake.Messages())
+	}
+
+	fake.Reset()
+	fake.Err = errors.New("down")
+	if err := fake.Send(context.Background(), Message{ChatID: 7}); err == nil || len(fake.Messages()) != 0 {
+		t.Fatalf("Err not returned or message stored: %v, %+v", err, fake.Messages())
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import "text/template"
+
+// Имена шаблонов уведомлений
+const (
+	TemplateJoinRequest        = "join_request"
+	TemplateRequestApproved    = "request_approved"
+	TemplateRequestRejected    = "request_rejected"
+	TemplateEventThanks        = "event_thanks"
+	TemplateEventSummary       = "event_summary"
+	TemplateEventReminder      = "event_reminder"
+	TemplateEventChanged       = "event_changed"
+	TemplateEventCancelled     = "event_cancelled"
+	TemplateRequestWaitlisted  = "request_waitlisted"
+	TemplateWaitlistPromoted   = "waitlist_promoted"
+	TemplateParticipantLeft    = "participant_left"
+	TemplateParticipantRemoved = "participant_removed"
+	TemplateJoinDigest         = "join_digest"
+	TemplateRequestWithdrawn   = "request_withdrawn"
+)
+
+// Данные для шаблонов передаются как map или структура с полями, которые используются ниже.
+// Date — дата события, уже переведённая в часовой пояс получателя.
+var templates = template.Must(template.New("notifier").Parse(`
+{{define "join_request"}}🆕 Новый запрос на участие!
+
+Событие: «{{.Event.Title}}»
+От пользователя: {{.User.DisplayName}}
+
+Принять или отклонить?{{end}}
+
+{{define "request_approved"}}🎉 Ваша заявка на участие в событии «{{.Event.Title}}» одобрена!
+Не сможете прийти — освободите место: /leave_{{.Event.ID}}{{end}}
+
+{{define "request_waitlisted"}}⏳ Заявка на участие в событии «{{.Event.Title}}» одобрена, но все места заняты — вы в листе ожидания. Мы сообщим, как только место освободится.
+Выйти из очереди: /leave_{{.Event.ID}}{{end}}
+
+{{define "waitlist_promoted"}}🎉 Освободилось место! Вы участник события «{{.Event.Title}}».
+Не сможете прийти — освободите место: /leave_{{.Event.ID}}{{end}}
+
+{{define "participant_left"}}👋 {{.User.DisplayName}} больше не участвует в событии «{{.Event.Title}}».{{end}}
+
+{{define "request_withdrawn"}}↩️ Заявка {{.User.DisplayName}} на участие в событии «{{.Event.Title}}» отозвана.{{end}}
+
+{{define "participant_removed"}}🚪 Организатор исключил вас из участников события «{{.Event.Title}}».{{end}}
+
+{{define "join_digest"}}📬 Новые участники события «{{.Title}}»:{{range .Joined}}
+• {{.}}{{end}}{{with .Waitlisted}}
+
+⏳ В листе ожидания:{{range .}}
+• {{.}}{{end}}{{end}}{{end}}
+
+{{define "request_rejected"}}😔 Ваша заявка на участие в событии «{{.Event.Title}}» отклонена.{{end}}
+
+{{define "event_thanks"}}🙏 Спасибо, что были на событии «{{.Event.Title}}»! Ждём вас снова.{{end}}
+
+{{define "event_summary"}}🏁 Событие «{{.Event.Title}}» завершено и закрыто.
+Участников: {{.Count}}{{end}}
+
+{{define "event_reminder"}}⏰ Напоминание! Событие «{{.Reminder.Title}}» начнётся {{.Date.Format "02.01.2006 15:04"}} (через {{.Left}}).{{with .Reminder.Location}}
+📍 {{.}}{{end}}{{end}}
+
+{{define "event_changed"}}✏️ Организатор изменил событие «{{.Event.Title}}».
+📅 Дата: {{.Date.Format "02.01.2006 15:04"}}
+📍 Место: {{.Event.Location}}{{end}}
+
+{{define "event_cancelled"}}🚫 Событие «{{.Event.Title}}» ({{.Date.Format "02.01.2006"}}) отменено организатором.{{end}}
+`))
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"tg-bot/internal/models"
+)
+
+// TestRenderTemplates рендерит каждый шаблон с теми данными, которые передают сервисы
+func TestRenderTemplates(t *testing.T) {
+	date := time.Date(2025, 11, 15, 19, 0, 0, 0, time.UTC)
+	event := models.Event{ID: 42, Title: "Кино", Location: "Парк", Date: date}
+	user := models.User{ChatID: 7, Username: "alice"}
+	// Пользователь без username в Telegram
+	anonymous := models.User{ChatID: 8}
+	reminder := models.Reminder{EventID: 42, Title: "Кино", Location: "Парк", Date: date}
+	digest := struct {
+		Title         string
+		CreatorChatID int64
+		Joined        []string
+		Waitlisted    []string
+	}{Title: "Кино", Joined: []string{"@alice", "id8"}, Waitlisted: []string{"id9"}}
+
+	tests := []struct {
+		name string
+		data any
+		want []string
+	}{
+		{TemplateJoinRequest, map[string]any{"Event": event, "User": user}, []string{"«Кино»", "@alice"}},
+		{TemplateJoinRequest, map[string]any{"Event": event, "User": anonymous}, []string{"От пользователя: id8"}},
+		{TemplateRequestApproved, map[string]any{"Event": event}, []string{"«Кино»", "/leave_42"}},
+		{TemplateRequestRejected, map[string]any{"Event": event}, []string{"«Кино»", "отклонена"}},
+		{TemplateRequestWaitlisted, map[string]any{"Event": event}, []string{"листе ожидания", "/leave_42"}},
+		{TemplateWaitlistPromoted, map[string]any{"Event": event}, []string{"Освободилось место", "/leave_42"}},
+		{TemplateParticipantLeft, map[string]any{"Event": event, "User": user}, []string{"@alice", "«Кино»"}},
+		{TemplateParticipantLeft, map[string]any{"Event": event, "User": anonymous}, []string{"👋 id8 "}},
+		{TemplateRequestWithdrawn, map[string]any{"Event": event, "User": user}, []string{"@alice", "отозвана"}},
+		{TemplateRequestWithdrawn, map[string]any{"Event": event, "User": anonymous}, []string{"Заявка id8 на участие"}},
+		{TemplateParticipantRemoved, map[string]any{"Event": event}, []string{"исключил", "«Кино»"}},
+		{TemplateJoinDigest, digest, []string{"«Кино»", "• @alice", "• id8", "В листе ожидания:\n• id9"}},
+		{TemplateEventThanks, map[string]any{"Event": event}, []string{"«Кино»"}},
+		{TemplateEventSummary, map[string]any{"Event": event, "Count": 3}, []string{"«Кино»", "Участников: 3"}},
+		{TemplateEventReminder, map[string]any{"Reminder": reminder, "Date": date, "Left": "1 ч"},
+			[]string{"«Кино»", "15.11.2025 19:00", "через 1 ч", "📍 Парк"}},
+		{TemplateEventChanged, map[string]any{"Event": event, "Date": date}, []string{"15.11.2025 19:00", "Место: Парк"}},
+		{TemplateEventCancelled, map[string]any{"Event": event, "Date": date}, []string{"«Кино»", "15.11.2025", "отменено"}},
+	}
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		covered[tt.name] = true
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := Render(tt.name, tt.data)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if strings.Contains(text, "<no value>") {
+				t.Fatalf("missing data in %q", text)
+			}
+			if strings.Contains(text, "@ ") || strings.HasSuffix(text, "@") || strings.Contains(text, "@\n") {
+				t.Fatalf("bare @ in %q", text)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(text, want) {
+					t.Errorf("%q does not contain %q", text, want)
+				}
+			}
+		})
+	}
+
+	// Новый шаблон без теста — ошибка: его данные никто не проверит
+	for _, tmpl := range templates.Templates() {
+		if name := tmpl.Name(); name != templates.Name() && !covered[name] {
+			t.Errorf("template %q is not covered by TestRenderTemplates", name)
+		}
+	}
+}
+
+func TestRenderJoinDigestWithoutWaitlist(t *testing.T) {
+	text, err := Render(TemplateJoinDigest, map[string]any{"Title": "Кино", "Joined": []string{"@alice"}})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if strings.Contains(text, "листе ожидания") {
+		t.Fatalf("empty waitlist rendered: %q", text)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	if _, err := Render("no_such_template", nil); err == nil {
+		t.Fatal("want error for unknown template")
+	}
+}
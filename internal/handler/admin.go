@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"tg-bot/internal/models"
+)
+
+const (
+	// deadLettersShown — сколько сообщений DLQ показывать в /dlq
+	deadLettersShown = 10
+	// replayBatch — сколько сообщений DLQ переотправлять за один /dlq_replay
+	replayBatch = 100
+)
+
+func (h *Handlers) isAdmin(chatID int64) bool {
+	_, ok := h.admins[chatID]
+	return ok
+}
+
+// handleDeadLettersCommand показывает администратору сообщения, которые консьюмер не смог обработать
+func (h *Handlers) handleDeadLettersCommand(chatID int64) {
+	if !h.isAdmin(chatID) {
+		h.Send(chatID, "⛔️ Команда доступна только администраторам")
+		return
+	}
+	letters, total, err := h.Services.DeadLetters.List(deadLettersShown)
+	if err != nil {
+		h.Send(chatID, "Не удалось прочитать DLQ 😢")
+		return
+	}
+	if total == 0 {
+		h.Send(chatID, "✅ DLQ пуста: все сообщения обработаны")
+		return
+	}
+	keyboard := &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+		{{Text: "🔁 Переотправить все", CallbackData: "dlq_replay"}},
+	}}
+	h.SendWithKeyboard(chatID, renderDeadLetters(letters, total, h.location(chatID)), keyboard)
+}
+
+func (h *Handlers) handleReplayCommand(chatID int64) {
+	if !h.isAdmin(chatID) {
+		h.Send(chatID, "⛔️ Команда доступна только администраторам")
+		return
+	}
+	h.Send(chatID, h.replayDeadLetters())
+}
+
+func (h *Handlers) handleReplayCallback(query *telego.CallbackQuery) {
+	if !h.isAdmin(query.From.ID) {
+		h.answerCallback(query.ID, "⛔️ Только для администраторов")
+		return
+	}
+	h.answerCallback(query.ID, "")
+	h.editCallbackPage(query, h.replayDeadLetters(), nil)
+}
+
+// replayDeadLetters возвращает сообщения из DLQ в исходные очереди и формирует ответ
+func (h *Handlers) replayDeadLetters() string {
+	replayed, err := h.Services.DeadLetters.Replay(replayBatch)
+	if err != nil {
+		return fmt.Sprintf("⚠️ Переотправлено %d, затем ошибка: %v", replayed, err)
+	}
+	if replayed == 0 {
+		return "✅ DLQ пуста: переотправлять нечего"
+	}
+	return fmt.Sprintf("🔁 Переотправлено сообщений: %d. Остаток: /dlq", replayed)
+}
+
+// renderDeadLetters — список сообщений DLQ; время сбоя в поясе администратора loc
+func renderDeadLetters(letters []models.DeadLetter, total int, loc *time.Location) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📮 В DLQ сообщений: %d (показаны первые %d)\n", total, len(letters))
+	for i, letter := range letters {
+		kind := letter.Type
+		if kind == "" {
+			kind = "неизвестный тип"
+		}
+		fmt.Fprintf(&b, "\n%d. %s · %s\n", i+1, letter.Queue, kind)
+		fmt.Fprintf(&b, "🔁 Попыток: %d", letter.Attempts)
+		if !letter.FailedAt.IsZero() {
+			fmt.Fprintf(&b, " · ⏱ %s", formatDateTime(letter.FailedAt.In(loc)))
+		}
+		b.WriteString("\n")
+		if letter.LastError != "" {
+			fmt.Fprintf(&b, "❗️ %s\n", truncateRunes(letter.LastError, 150))
+		}
+		fmt.Fprintf(&b, "📦 %s\n", truncateRunes(string(letter.Body), 150))
+	}
+	b.WriteString("\nПереотправить в обработку: /dlq_replay")
+	return b.String()
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+)
+
+// setEventCategory привязывает событие к категории каталога
+func setEventCategory(event *models.Event, category models.Category) {
+	id := category.ID
+	event.CategoryID = &id
+	event.Category = category.Title
+}
+
+// categoriesKeyboard — кнопки категорий по две в ряд
+func categoriesKeyboard(categories []models.Category, callbackPrefix string, withCount bool) *telego.InlineKeyboardMarkup {
+	var rows [][]telego.InlineKeyboardButton
+	for i, c := range categories {
+		text := c.Title
+		if withCount {
+			text = fmt.Sprintf("%s (%d)", c.Title, c.EventCount)
+		}
+		button := telego.InlineKeyboardButton{Text: text, CallbackData: fmt.Sprintf("%s%d", callbackPrefix, c.ID)}
+		if i%2 == 0 {
+			rows = append(rows, []telego.InlineKeyboardButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// askCategory предлагает выбрать категорию кнопками в мастерах создания и редактирования
+func (h *Handlers) askCategory(chatID int64, prompt string) {
+	categories, err := h.Services.GetCategories()
+	if err != nil || len(categories) == 0 {
+		logrus.Infof("Error getting categories: %v", err)
+		h.Send(chatID, "Ошибка при получении категорий 😢")
+		return
+	}
+	h.SendWithKeyboard(chatID, prompt, categoriesKeyboard(categories, "setcat_", false))
+}
+
+// handleSetCategoryCallback применяет выбранную кнопкой категорию к активному мастеру
+func (h *Handlers) handleSetCategoryCallback(query *telego.CallbackQuery, categoryID int64) {
+	chatID := query.From.ID
+	state := h.getState(chatID)
+	if state == nil || !(state.Step == "category" || (state.Step == "edit_value" && state.Field == "category")) {
+		h.answerCallback(query.ID, "Сейчас категорию выбирать не нужно")
+		return
+	}
+	category, err := h.Services.GetCategory(categoryID)
+	if err != nil {
+		h.answerCallback(query.ID, "Категория не найдена")
+		return
+	}
+	h.answerCallback(query.ID, category.Title)
+	h.editCallbackText(query, "🗂 Категория: "+category.Title)
+
+	setEventCategory(&state.Event, category)
+	if state.Step == "edit_value" {
+		h.saveEditedEvent(chatID, state.Event, state.Field)
+		return
+	}
+	state.Step = "description"
+	h.setState(chatID, state)
+	h.Send(chatID, "📝 Введите описание:")
+}
+
+func (h *Handlers) handleCategoriesCommand(chatID int64) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	categories, err := h.Services.GetCategories()
+	if err != nil {
+		h.Send(chatID, "Ошибка при получении категорий 😢")
+		return
+	}
+	if len(categories) == 0 {
+		h.Send(chatID, "Категорий пока нет")
+		return
+	}
+	h.SendWithKeyboard(chatID, "🗂 Категории (в скобках — число предстоящих событий):", categoriesKeyboard(categories, "cat_", true))
+}
+
+// handleCategoryFeedCallback открывает ленту категории: "cat_<id>" — новым сообщением,
+// "catpage_<id>_<page>" — перелистывание в том же сообщении
+func (h *Handlers) handleCategoryFeedCallback(query *telego.CallbackQuery) {
+	data := query.Data
+	paging := strings.HasPrefix(data, "catpage_")
+	data = strings.TrimPrefix(strings.TrimPrefix(data, "catpage_"), "cat_")
+	idStr, pageStr, _ := strings.Cut(data, "_")
+
+	categoryID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверная категория")
+		return
+	}
+	pageNum := 0
+	if paging {
+		if pageNum, err = strconv.Atoi(pageStr); err != nil {
+			h.answerCallback(query.ID, "Неверная страница")
+			return
+		}
+	}
+
+	category, err := h.Services.GetCategory(categoryID)
+	if err != nil {
+		h.answerCallback(query.ID, "Категория не найдена")
+		return
+	}
+	page, err := h.Services.Events.GetEventsByCategory(categoryID, pageNum)
+	if err != nil {
+		h.answerCallback(query.ID, "Ошибка при получении событий 😢")
+		return
+	}
+	if page.Total == 0 {
+		h.answerCallback(query.ID, "В этой категории пока нет событий")
+		return
+	}
+	h.answerCallback(query.ID, "")
+
+	text, keyboard := renderEventsPage("🗂 "+category.Title, page, fmt.Sprintf("catpage_%d_", categoryID), h.location(query.From.ID))
+	if paging {
+		h.editCallbackPage(query, text, keyboard)
+		return
+	}
+	h.SendWithKeyboard(query.From.ID, text, keyboard)
+}
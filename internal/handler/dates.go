@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+)
+
+// eventDateLayouts — форматы даты и времени события, которые понимает мастер
+var eventDateLayouts = []string{
+	"02.01.2006 15:04",
+	"2006-01-02 15:04",
+	"02.01.2006",
+	"2006-01-02",
+}
+
+// errDateInPast — дата события уже прошла; общая проверка для создания и редактирования
+var errDateInPast = errors.New("event date is in the past")
+
+// parseEventDate разбирает дату события в часовом поясе now. Дата без года
+// («15.11 19:00») относится к ближайшему будущему; прошедшая дата — errDateInPast.
+func parseEventDate(input string, now time.Time) (time.Time, error) {
+	t, err := parseEventDateText(input, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t.Before(now) {
+		return time.Time{}, errDateInPast
+	}
+	return t, nil
+}
+
+func parseEventDateText(input string, now time.Time) (time.Time, error) {
+	loc := now.Location()
+	text := strings.Join(strings.Fields(input), " ")
+	for _, layout := range eventDateLayouts {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range []string{"02.01 15:04", "02.01"} {
+		t, err := time.ParseInLocation(layout, text, loc)
+		if err != nil {
+			continue
+		}
+		// Разобранная дата — в нулевом (високосном) году, поэтому год подбираем через nearestDate
+		date, ok := nearestDate(t.Month(), t.Day(), now)
+		if !ok {
+			break
+		}
+		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, loc), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown date format: %q", input)
+}
+
+// eventDateErrorText — текст для пользователя по ошибке parseEventDate
+func eventDateErrorText(err error) string {
+	if errors.Is(err, errDateInPast) {
+		return "Дата уже прошла, укажите будущую дату"
+	}
+	return "Неверный формат даты. Попробуйте ДД.ММ.ГГГГ ЧЧ:ММ, например 15.11.2025 19:00"
+}
+
+// dateRange — полуинтервал [From, To) для фильтра событий по дате
+type dateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func dayRange(day time.Time) dateRange {
+	from := startOfDay(day)
+	return dateRange{From: from, To: from.AddDate(0, 0, 1)}
+}
+
+// weekendRange — ближайшие выходные; в субботу и воскресенье — текущие
+func weekendRange(now time.Time) dateRange {
+	today := startOfDay(now)
+	switch now.Weekday() {
+	case time.Saturday:
+		return dateRange{From: today, To: today.AddDate(0, 0, 2)}
+	case time.Sunday:
+		return dateRange{From: today, To: today.AddDate(0, 0, 1)}
+	}
+	saturday := today.AddDate(0, 0, int(time.Saturday-now.Weekday()))
+	return dateRange{From: saturday, To: saturday.AddDate(0, 0, 2)}
+}
+
+var (
+	numericDateRe = regexp.MustCompile(`^(\d{1,2})[./](\d{1,2})(?:[./](\d{4}))?$`)
+	textDateRe    = regexp.MustCompile(`^(\d{1,2})\s+(\p{L}+)(?:\s+(\d{4}))?$`)
+)
+
+// parseDateRange понимает «сегодня», «завтра», «послезавтра», «выходные», «неделя»,
+// а также даты «15.11», «15.11.2025» и «15 ноября». Дата без года, которая в этом году
+// уже прошла, относится к следующему году (см. nearestDate).
+func parseDateRange(input string, now time.Time) (dateRange, bool) {
+	text := strings.ToLower(strings.TrimSpace(input))
+	today := startOfDay(now)
+	switch text {
+	case "сегодня":
+		return dayRange(today), true
+	case "завтра":
+		return dayRange(today.AddDate(0, 0, 1)), true
+	case "послезавтра":
+		return dayRange(today.AddDate(0, 0, 2)), true
+	case "выходные", "на выходных":
+		return weekendRange(now), true
+	case "неделя", "на неделе", "эта неделя":
+		return dateRange{From: today, To: today.AddDate(0, 0, 7)}, true
+	}
+
+	var day, month, year int
+	if m := numericDateRe.FindStringSubmatch(text); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		month, _ = strconv.Atoi(m[2])
+		year, _ = strconv.Atoi(m[3])
+	} else if m := textDateRe.FindStringSubmatch(text); m != nil {
+		day, _ = strconv.Atoi(m[1])
+		for i, name := range months {
+			if name == m[2] {
+				month = i + 1
+			}
+		}
+		year, _ = strconv.Atoi(m[3])
+	}
+	if month < 1 || month > 12 || day < 1 {
+		return dateRange{}, false
+	}
+
+	if year == 0 {
+		date, ok := nearestDate(time.Month(month), day, now)
+		if !ok {
+			return dateRange{}, false
+		}
+		return dayRange(date), true
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, now.Location())
+	if date.Day() != day {
+		// 31.02 и подобные — time.Date нормализует их в следующий месяц
+		return dateRange{}, false
+	}
+	return dayRange(date), true
+}
+
+// nearestDate — ближайший день day.month, не раньше сегодняшнего: в этом году или в следующем.
+// 29.02 без високосного года среди них не существует — ok == false.
+func nearestDate(month time.Month, day int, now time.Time) (time.Time, bool) {
+	today := startOfDay(now)
+	for _, year := range []int{now.Year(), now.Year() + 1} {
+		date := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+		if date.Day() == day && !date.Before(today) {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rangeTitle — заголовок ленты для диапазона дат
+func rangeTitle(r dateRange) string {
+	last := r.To.AddDate(0, 0, -1)
+	if !last.After(r.From) {
+		return "📅 События на " + formatDay(r.From)
+	}
+	return fmt.Sprintf("📅 События с %s по %s", formatDay(r.From), formatDay(last))
+}
+
+// dateFilterKeyboard — быстрые фильтры по датам
+func dateFilterKeyboard() *telego.InlineKeyboardMarkup {
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+		{
+			{Text: "Сегодня", CallbackData: "when_сегодня"},
+			{Text: "Завтра", CallbackData: "when_завтра"},
+		},
+		{
+			{Text: "Выходные", CallbackData: "when_выходные"},
+			{Text: "Неделя", CallbackData: "when_неделя"},
+		},
+	}}
+}
+
+// handleWhenCommand предлагает выбрать период кнопкой или ввести дату текстом
+func (h *Handlers) handleWhenCommand(chatID int64) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	h.setState(chatID, &models.UserState{Step: "date_filter", ChatID: chatID})
+	h.SendWithKeyboard(chatID, "📅 Когда? Выберите период или напишите дату: «завтра», «15.11», «15 ноября»", dateFilterKeyboard())
+}
+
+// handleDateFilter показывает первую страницу событий за период, заданный текстом
+func (h *Handlers) handleDateFilter(chatID int64, input string) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	r, ok := parseDateRange(input, time.Now().In(h.location(chatID)))
+	if !ok {
+		h.Send(chatID, "Не понял дату 🤔 Примеры: «сегодня», «завтра», «выходные», «15.11», «15 ноября»")
+		return
+	}
+	h.sendRangePage(chatID, nil, r, 0)
+}
+
+// sendRangePage отправляет страницу событий за период; при query != nil — редактирует сообщение
+func (h *Handlers) sendRangePage(chatID int64, query *telego.CallbackQuery, r dateRange, pageNum int) {
+	page, err := h.Services.Events.GetEventsInRange(r.From, r.To, pageNum)
+	if err != nil {
+		logrus.Infof("Error getting events in range: %s", err)
+		h.Send(chatID, "Ошибка при получении событий")
+		return
+	}
+	if page.Total == 0 {
+		h.Send(chatID, rangeTitle(r)+"\n\nСобытий нет")
+		return
+	}
+	text, keyboard := renderEventsPage(rangeTitle(r), page, fmt.Sprintf("range_%d_%d_", r.From.Unix(), r.To.Unix()), r.From.Location())
+	if query != nil {
+		h.editCallbackPage(query, text, keyboard)
+		return
+	}
+	h.SendWithKeyboard(chatID, text, keyboard)
+}
+
+// handleRangePageCallback перелистывает ленту периода. payload: "<fromUnix>_<toUnix>_<page>"
+func (h *Handlers) handleRangePageCallback(query *telego.CallbackQuery, payload string) {
+	parts := strings.Split(payload, "_")
+	if len(parts) != 3 {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	from, err1 := strconv.ParseInt(parts[0], 10, 64)
+	to, err2 := strconv.ParseInt(parts[1], 10, 64)
+	pageNum, err3 := strconv.Atoi(parts[2])
+	if err1 != nil || err2 != nil || err3 != nil {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	h.answerCallback(query.ID, "")
+	loc := h.location(query.From.ID)
+	h.sendRangePage(query.From.ID, query, dateRange{From: time.Unix(from, 0).In(loc), To: time.Unix(to, 0).In(loc)}, pageNum)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var almaty = time.FixedZone("Asia/Almaty", 5*60*60)
+
+func localDay(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, almaty)
+}
+
+func TestParseDateRange(t *testing.T) {
+	// Суббота, 20 декабря 2025, 15:00
+	now := time.Date(2025, time.December, 20, 15, 0, 0, 0, almaty)
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		from  time.Time
+		days  int
+		ok    bool
+	}{
+		{"today", "Сегодня", now, localDay(2025, time.December, 20), 1, true},
+		{"tomorrow", "завтра", now, localDay(2025, time.December, 21), 1, true},
+		{"day after tomorrow", "послезавтра", now, localDay(2025, time.December, 22), 1, true},
+		{"weekend", "выходные", now, localDay(2025, time.December, 20), 2, true},
+		{"week", "неделя", now, localDay(2025, time.December, 20), 7, true},
+		{"numeric this year", "25.12", now, localDay(2025, time.December, 25), 1, true},
+		{"numeric today", "20.12", now, localDay(2025, time.December, 20), 1, true},
+		// Дата без года, которая уже прошла, — в следующем году
+		{"numeric rollover", "10.01", now, localDay(2026, time.January, 10), 1, true},
+		{"text rollover", "15 ноября", now, localDay(2026, time.November, 15), 1, true},
+		{"text this year", "31 декабря", now, localDay(2025, time.December, 31), 1, true},
+		{"explicit past year kept", "15.11.2025", now, localDay(2025, time.November, 15), 1, true},
+		{"explicit year with slash", "1/3/2026", now, localDay(2026, time.March, 1), 1, true},
+		{"leap day with year", "29.02.2028", now, localDay(2028, time.February, 29), 1, true},
+		{"leap day in non-leap year", "29.02.2026", now, time.Time{}, 0, false},
+		// В 2025 и 2026 годах 29 февраля нет — не превращаем его в 1 марта
+		{"leap day without year in non-leap year", "29.02", now, time.Time{}, 0, false},
+		{"leap day without year before leap year", "29 февраля",
+			time.Date(2027, time.March, 10, 12, 0, 0, 0, almaty), localDay(2028, time.February, 29), 1, true},
+		{"leap day without year after it passed", "29.02",
+			time.Date(2028, time.March, 10, 12, 0, 0, 0, almaty), time.Time{}, 0, false},
+		{"invalid day", "31.02", now, time.Time{}, 0, false},
+		{"invalid month", "10.13", now, time.Time{}, 0, false},
+		{"unknown month", "10 брюмера", now, time.Time{}, 0, false},
+		{"garbage", "когда-нибудь", now, time.Time{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := parseDateRange(tt.input, tt.now)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v (range %v)", ok, tt.ok, r)
+			}
+			if !ok {
+				return
+			}
+			want := dateRange{From: tt.from, To: tt.from.AddDate(0, 0, tt.days)}
+			if !r.From.Equal(want.From) || !r.To.Equal(want.To) {
+				t.Fatalf("got [%s, %s), want [%s, %s)", r.From, r.To, want.From, want.To)
+			}
+		})
+	}
+}
+
+func TestWeekendRange(t *testing.T) {
+	tests := []struct {
+		name string
+		now  time.Time
+		from time.Time
+		to   time.Time
+	}{
+		{"monday", time.Date(2025, time.December, 15, 9, 0, 0, 0, almaty),
+			localDay(2025, time.December, 20), localDay(2025, time.December, 22)},
+		{"friday", time.Date(2025, time.December, 19, 23, 59, 0, 0, almaty),
+			localDay(2025, time.December, 20), localDay(2025, time.December, 22)},
+		{"saturday", time.Date(2025, time.December, 20, 0, 0, 0, 0, almaty),
+			localDay(2025, time.December, 20), localDay(2025, time.December, 22)},
+		// В воскресенье выходные — только оставшийся день, а не следующая суббота
+		{"sunday", time.Date(2025, time.December, 21, 18, 0, 0, 0, almaty),
+			localDay(2025, time.December, 21), localDay(2025, time.December, 22)},
+		{"across year", time.Date(2026, time.December, 31, 12, 0, 0, 0, almaty),
+			localDay(2027, time.January, 2), localDay(2027, time.January, 4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := weekendRange(tt.now)
+			if !r.From.Equal(tt.from) || !r.To.Equal(tt.to) {
+				t.Fatalf("got [%s, %s), want [%s, %s)", r.From, r.To, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestParseEventDate(t *testing.T) {
+	// Суббота, 20 декабря 2025, 15:00
+	now := time.Date(2025, time.December, 20, 15, 0, 0, 0, almaty)
+	at := func(year int, month time.Month, d, hour, minute int) time.Time {
+		return time.Date(year, month, d, hour, minute, 0, 0, almaty)
+	}
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		want  time.Time
+		past  bool
+		fails bool
+	}{
+		{"full date and time", "25.12.2025 19:00", now, at(2025, time.December, 25, 19, 0), false, false},
+		{"iso date and time", "2026-01-05  18:30", now, at(2026, time.January, 5, 18, 30), false, false},
+		{"date only", "25.12.2025", now, at(2025, time.December, 25, 0, 0), false, false},
+		{"later today", "20.12 19:00", now, at(2025, time.December, 20, 19, 0), false, false},
+		{"no year this year", "31.12 23:00", now, at(2025, time.December, 31, 23, 0), false, false},
+		// Дата без года, которая уже прошла, — в следующем году
+		{"no year rollover", "10.01 12:00", now, at(2026, time.January, 10, 12, 0), false, false},
+		{"leap day without year before leap year", "29.02 10:00",
+			time.Date(2027, time.March, 10, 12, 0, 0, 0, almaty), at(2028, time.February, 29, 10, 0), false, false},
+		// В 2025 и 2026 годах 29 февраля нет — не превращаем его в 1 марта
+		{"leap day without year in non-leap year", "29.02 10:00", now, time.Time{}, false, true},
+		{"leap day in non-leap year", "29.02.2026 10:00", now, time.Time{}, false, true},
+		{"earlier today", "20.12 10:00", now, time.Time{}, true, false},
+		{"explicit past date", "15.11.2025 19:00", now, time.Time{}, true, false},
+		{"garbage", "завтра вечером", now, time.Time{}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEventDate(tt.input, tt.now)
+			switch {
+			case tt.past:
+				if !errors.Is(err, errDateInPast) {
+					t.Fatalf("want errDateInPast, got %v (%s)", err, got)
+				}
+			case tt.fails:
+				if err == nil || errors.Is(err, errDateInPast) {
+					t.Fatalf("want format error, got %v (%s)", err, got)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case !got.Equal(tt.want):
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/repository"
+)
+
+// myEventKeyboard — кнопки под карточкой события в /my_events
+func myEventKeyboard(eventID int64) *telego.InlineKeyboardMarkup {
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+		{
+			{Text: "✏️ Редактировать", CallbackData: fmt.Sprintf("edit_%d", eventID)},
+			{Text: "🗑 Удалить", CallbackData: fmt.Sprintf("delete_%d", eventID)},
+		},
+		{
+			{Text: "👥 Участники", CallbackData: fmt.Sprintf("members_%d", eventID)},
+		},
+	}}
+}
+
+// handleDeleteCommand спрашивает подтверждение перед удалением события
+func (h *Handlers) handleDeleteCommand(chatID, eventID int64) {
+	event, ok := h.getOwnEvent(chatID, eventID)
+	if !ok {
+		return
+	}
+	keyboard := &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+		{
+			{Text: "🗑 Да, удалить", CallbackData: fmt.Sprintf("delete_confirm_%d", event.ID)},
+			{Text: "↩️ Отмена", CallbackData: fmt.Sprintf("delete_cancel_%d", event.ID)},
+		},
+	}}
+	h.SendWithKeyboard(chatID, fmt.Sprintf("Удалить событие «%s»? Все участники и заявки получат уведомление об отмене.", event.Title), keyboard)
+}
+
+// handleDeleteCallback обрабатывает кнопки удаления. payload: "<id>", "confirm_<id>" или "cancel_<id>".
+func (h *Handlers) handleDeleteCallback(query *telego.CallbackQuery, payload string) {
+	chatID := query.From.ID
+	action := ""
+	if a, rest, found := strings.Cut(payload, "_"); found {
+		action, payload = a, rest
+	}
+	eventID, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверный ID события")
+		return
+	}
+
+	switch action {
+	case "":
+		h.answerCallback(query.ID, "")
+		h.handleDeleteCommand(chatID, eventID)
+		return
+	case "cancel":
+		h.answerCallback(query.ID, "Удаление отменено")
+		h.editCallbackText(query, "↩️ Удаление отменено")
+		return
+	case "confirm":
+	default:
+		h.answerCallback(query.ID, "Неизвестное действие")
+		return
+	}
+
+	err = h.Services.Events.DeleteEvent(eventID, chatID)
+	switch {
+	case errors.Is(err, repository.ErrNotEventOwner):
+		h.answerCallback(query.ID, "Это действие доступно только создателю события")
+		return
+	case err != nil:
+		h.answerCallback(query.ID, "Ошибка при удалении события 😢")
+		return
+	}
+	h.answerCallback(query.ID, "Событие удалено")
+	h.editCallbackText(query, fmt.Sprintf("🗑 Событие ID %d удалено, участники уведомлены", eventID))
+}
+
+// editCallbackText заменяет текст сообщения, на кнопку которого нажали, и убирает кнопки
+func (h *Handlers) editCallbackText(query *telego.CallbackQuery, text string) {
+	if query.Message == nil {
+		return
+	}
+	_, err := h.Bot.EditMessageText(context.Background(), &telego.EditMessageTextParams{
+		ChatID:    telego.ChatID{ID: query.Message.GetChat().ID},
+		MessageID: query.Message.GetMessageID(),
+		Text:      text,
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка редактирования сообщения: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+	"tg-bot/internal/service"
+)
+
+// editableFields — поля, доступные в мастере редактирования, в порядке кнопок
+var editableFields = []struct {
+	key    string
+	title  string
+	prompt string
+}{
+	{"title", "Название", "🎬 Введите новое название:"},
+	{"category", "Категория", "🗂 Выберите новую категорию:"},
+	{"description", "Описание", "📝 Введите новое описание:"},
+	{"date", "Дата", "📅 Введите новую дату и время (ДД.ММ.ГГГГ ЧЧ:ММ):"},
+	{"location", "Место", "📍 Укажите новое место текстом или отправьте точку на карте:"},
+	{"url", "Ссылка", "🔗 Вставьте новую ссылку (или «-», чтобы удалить):"},
+	{"capacity", "Мест", "👥 Введите максимум участников (или «-», чтобы снять ограничение):"},
+	{"image", "Изображение", "🖼 Отправьте фото афиши или ссылку на изображение (или «-», чтобы удалить):"},
+}
+
+// getOwnEvent возвращает событие, только если chatID — его создатель
+func (h *Handlers) getOwnEvent(chatID, eventID int64) (models.Event, bool) {
+	event, err := h.Services.Events.GetByID(eventID)
+	if err != nil {
+		h.Send(chatID, "Событие не найдено")
+		return models.Event{}, false
+	}
+	if event.CreatorTgID != chatID {
+		h.Send(chatID, "Это действие доступно только создателю события")
+		return models.Event{}, false
+	}
+	return event, true
+}
+
+func (h *Handlers) handleEditCommand(chatID, eventID int64) {
+	event, ok := h.getOwnEvent(chatID, eventID)
+	if !ok {
+		return
+	}
+
+	var rows [][]telego.InlineKeyboardButton
+	for i, f := range editableFields {
+		button := telego.InlineKeyboardButton{Text: f.title, CallbackData: fmt.Sprintf("editfield_%d_%s", event.ID, f.key)}
+		if i%2 == 0 {
+			rows = append(rows, []telego.InlineKeyboardButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	_, err := h.Bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		Text:        "✏️ Что изменить?\n\n" + formatEventCard(event, h.location(chatID)),
+		ReplyMarkup: &telego.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка отправки меню редактирования: %v", err)
+	}
+}
+
+// handleEditFieldCallback запоминает выбранное поле и просит ввести новое значение.
+// payload имеет вид "<eventID>_<field>".
+func (h *Handlers) handleEditFieldCallback(query *telego.CallbackQuery, payload string) {
+	chatID := query.From.ID
+	idStr, field, found := strings.Cut(payload, "_")
+	if !found {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	eventID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверный ID события")
+		return
+	}
+	prompt := ""
+	for _, f := range editableFields {
+		if f.key == field {
+			prompt = f.prompt
+		}
+	}
+	if prompt == "" {
+		h.answerCallback(query.ID, "Неизвестное поле")
+		return
+	}
+	h.answerCallback(query.ID, "")
+
+	event, ok := h.getOwnEvent(chatID, eventID)
+	if !ok {
+		return
+	}
+	h.setState(chatID, &models.UserState{Step: "edit_value", ChatID: chatID, Event: event, Field: field})
+	if field == "category" {
+		h.askCategory(chatID, prompt)
+		return
+	}
+	h.Send(chatID, prompt)
+}
+
+// handleEditValue применяет введённое значение и сохраняет событие
+func (h *Handlers) handleEditValue(chatID int64, state *models.UserState, message *telego.Message) {
+	event := state.Event
+	// Для афиши принимаем и присланное фото: сохраняем его file_id
+	if photoID := largestPhotoID(message); photoID != "" && state.Field == "image" {
+		event.ImageURL = &photoID
+	} else if state.Field == "location" && setEventGeo(&event, message) {
+		// Геопозиция без подписи обновляет только координаты, текст места остаётся прежним
+	} else if state.Field == "category" {
+		category, err := h.Services.FindCategory(message.Text)
+		if err != nil {
+			h.askCategory(chatID, "Такой категории нет, выберите из списка:")
+			return
+		}
+		setEventCategory(&event, category)
+	} else if err := applyEventField(&event, state.Field, strings.TrimSpace(message.Text), h.location(chatID)); err != nil {
+		// Оставляем состояние, чтобы пользователь мог ввести значение ещё раз
+		h.Send(chatID, err.Error())
+		return
+	}
+	h.saveEditedEvent(chatID, event, state.Field)
+}
+
+// saveEditedEvent завершает мастер редактирования и сохраняет отредактированное поле field
+func (h *Handlers) saveEditedEvent(chatID int64, event models.Event, field string) {
+	h.clearState(chatID)
+
+	event, err := h.Services.Events.UpdateEvent(event, field, chatID)
+	switch {
+	case errors.Is(err, repository.ErrNotEventOwner):
+		h.Send(chatID, "Это действие доступно только создателю события")
+		return
+	case errors.Is(err, service.ErrInvalidEvent):
+		h.Send(chatID, "Событие заполнено некорректно, изменения не сохранены")
+		return
+	case err != nil:
+		h.Send(chatID, "Ошибка при сохранении события 😢")
+		return
+	}
+	h.sendEventCard(chatID, event, "✅ Событие обновлено!\n\n"+formatEventCard(event, h.location(chatID)), nil)
+}
+
+// applyEventField валидирует значение и записывает его в поле события.
+// Текст ошибки показывается пользователю. Дата вводится в часовом поясе автора loc.
+func applyEventField(event *models.Event, field, value string, loc *time.Location) error {
+	switch field {
+	case "title":
+		if value == "" {
+			return errors.New("Название не может быть пустым")
+		}
+		event.Title = value
+	case "description":
+		event.Description = value
+	case "date":
+		parsed, err := parseEventDate(value, time.Now().In(loc))
+		if err != nil {
+			return errors.New(eventDateErrorText(err))
+		}
+		event.Date = parsed
+	case "location":
+		if value == "" {
+			return errors.New("Место не может быть пустым")
+		}
+		// Новый адрес текстом — старые координаты больше не соответствуют месту
+		event.Location = value
+		event.Latitude, event.Longitude = nil, nil
+	case "url":
+		if value == "-" {
+			event.URL = ""
+			return nil
+		}
+		if !isHTTPURL(value) {
+			return errors.New("Ссылка должна начинаться с http:// или https://")
+		}
+		event.URL = value
+	case "capacity":
+		if value == "-" {
+			event.MaxParticipants = nil
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return errors.New("Введите целое число больше нуля или «-»")
+		}
+		event.MaxParticipants = &n
+	case "image":
+		if value == "-" {
+			event.ImageURL = nil
+			return nil
+		}
+		if !isHTTPURL(value) {
+			return errors.New("Ссылка должна начинаться с http:// или https://")
+		}
+		event.ImageURL = &value
+	default:
+		return errors.New("Неизвестное поле")
+	}
+	return nil
+}
+
+func isHTTPURL(value string) bool {
+	u, err := url.ParseRequestURI(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
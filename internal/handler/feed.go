@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+)
+
+var months = []string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"}
+
+// formatDay — «15 ноября»
+func formatDay(t time.Time) string {
+	return fmt.Sprintf("%d %s", t.Day(), months[t.Month()-1])
+}
+
+// formatDateTime — «15 ноября, 19:00»; время не показываем, если оно не указано (полночь)
+func formatDateTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return formatDay(t)
+	}
+	return fmt.Sprintf("%s, %s", formatDay(t), t.Format("15:04"))
+}
+
+// renderEventsPage собирает одну страницу ленты в текст и кнопки навигации.
+// pagePrefix — префикс callback-данных, к которому добавляется номер страницы.
+// Даты выводятся в часовом поясе зрителя loc.
+func renderEventsPage(title string, page models.EventPage, pagePrefix string, loc *time.Location) (string, *telego.InlineKeyboardMarkup) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (страница %d из %d, всего: %d)\n", title, page.Page+1, page.Pages(), page.Total)
+	for i, event := range page.Events {
+		fmt.Fprintf(&b, "\n%d. %s — %s\n", page.Page*page.PageSize+i+1, event.Title, formatDateTime(event.Date.In(loc)))
+		fmt.Fprintf(&b, "🗂 %s · 📍 %s\n", event.Category, event.Location)
+		if event.URL != "" {
+			fmt.Fprintf(&b, "🔗 %s\n", event.URL)
+		}
+		fmt.Fprintf(&b, "Участвовать: /apply_%d\n", event.ID)
+	}
+
+	var nav []telego.InlineKeyboardButton
+	if page.HasPrev() {
+		nav = append(nav, telego.InlineKeyboardButton{Text: "◀️", CallbackData: fmt.Sprintf("%s%d", pagePrefix, page.Page-1)})
+	}
+	if page.HasNext() {
+		nav = append(nav, telego.InlineKeyboardButton{Text: "▶️", CallbackData: fmt.Sprintf("%s%d", pagePrefix, page.Page+1)})
+	}
+	if len(nav) == 0 {
+		return b.String(), nil
+	}
+	return b.String(), &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{nav}}
+}
+
+// sendEventsList отправляет первую страницу ленты одним сообщением
+func (h *Handlers) sendEventsList(chatID int64) {
+	page, err := h.Services.Events.GetEvents(0)
+	if err != nil {
+		logrus.Infof("Error getting events: %s", err)
+		h.Send(chatID, "Ошибка при получении событий")
+		return
+	}
+	if page.Total == 0 {
+		h.Send(chatID, "Событий нет")
+		return
+	}
+	text, keyboard := renderEventsPage("📅 Ближайшие события", page, "events_page_", h.location(chatID))
+	h.SendWithKeyboard(chatID, text, keyboard)
+}
+
+// handleEventsPageCallback перелистывает ленту, редактируя исходное сообщение
+func (h *Handlers) handleEventsPageCallback(query *telego.CallbackQuery, pageNum int) {
+	page, err := h.Services.Events.GetEvents(pageNum)
+	if err == nil && len(page.Events) == 0 && page.Total > 0 {
+		// Пока листали, часть событий закрылась — показываем последнюю страницу
+		page, err = h.Services.Events.GetEvents(page.Pages() - 1)
+	}
+	if err != nil {
+		logrus.Infof("Error getting events: %s", err)
+		return
+	}
+	if page.Total == 0 {
+		h.editCallbackText(query, "Событий нет")
+		return
+	}
+	text, keyboard := renderEventsPage("📅 Ближайшие события", page, "events_page_", h.location(query.From.ID))
+	h.editCallbackPage(query, text, keyboard)
+}
+
+// editCallbackPage заменяет текст и кнопки сообщения, на кнопку которого нажали
+func (h *Handlers) editCallbackPage(query *telego.CallbackQuery, text string, keyboard *telego.InlineKeyboardMarkup) {
+	if query.Message == nil {
+		return
+	}
+	_, err := h.Bot.EditMessageText(context.Background(), &telego.EditMessageTextParams{
+		ChatID:      telego.ChatID{ID: query.Message.GetChat().ID},
+		MessageID:   query.Message.GetMessageID(),
+		Text:        text,
+		ReplyMarkup: keyboard,
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка перелистывания ленты: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+)
+
+// nearRadii — варианты радиуса поиска «рядом со мной», км
+var nearRadii = []int{1, 3, 5, 10, 25}
+
+// messageGeo достаёт координаты из присланной геопозиции или места (venue).
+// Для venue также возвращается подпись «Название, адрес».
+func messageGeo(message *telego.Message) (lat, lon float64, title string, ok bool) {
+	switch {
+	case message.Venue != nil:
+		title = message.Venue.Title
+		if message.Venue.Address != "" {
+			title += ", " + message.Venue.Address
+		}
+		return message.Venue.Location.Latitude, message.Venue.Location.Longitude, title, true
+	case message.Location != nil:
+		return message.Location.Latitude, message.Location.Longitude, "", true
+	}
+	return 0, 0, "", false
+}
+
+// setEventGeo записывает координаты из сообщения в событие; для venue заменяет и текст места.
+// Возвращает false, если в сообщении нет геоданных.
+func setEventGeo(event *models.Event, message *telego.Message) bool {
+	lat, lon, title, ok := messageGeo(message)
+	if !ok {
+		return false
+	}
+	event.Latitude, event.Longitude = &lat, &lon
+	if title != "" {
+		event.Location = title
+	}
+	return true
+}
+
+// mapURL — ссылка на точку события на карте
+func mapURL(event models.Event) string {
+	return fmt.Sprintf("https://maps.google.com/?q=%.6f,%.6f", *event.Latitude, *event.Longitude)
+}
+
+// formatDistance — «350 м» или «2.4 км»
+func formatDistance(km float64) string {
+	if km < 1 {
+		return fmt.Sprintf("%d м", int(km*1000))
+	}
+	return fmt.Sprintf("%.1f км", km)
+}
+
+func (h *Handlers) handleNearCommand(chatID int64) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	h.setState(chatID, &models.UserState{Step: "near_location", ChatID: chatID})
+	_, err := h.Bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID: telego.ChatID{ID: chatID},
+		Text:   "📍 Поделитесь геопозицией кнопкой ниже или отправьте точку на карте (📎 → Геопозиция):",
+		ReplyMarkup: &telego.ReplyKeyboardMarkup{
+			Keyboard:        [][]telego.KeyboardButton{{{Text: "📍 Отправить геопозицию", RequestLocation: true}}},
+			ResizeKeyboard:  true,
+			OneTimeKeyboard: true,
+		},
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка отправки запроса геопозиции: %v", err)
+	}
+}
+
+// handleNearLocation принимает точку пользователя и предлагает выбрать радиус
+func (h *Handlers) handleNearLocation(chatID int64, message *telego.Message) {
+	lat, lon, _, ok := messageGeo(message)
+	if !ok {
+		h.Send(chatID, "Нужна геопозиция: нажмите кнопку «📍 Отправить геопозицию» или отправьте точку на карте")
+		return
+	}
+	h.clearState(chatID)
+
+	// Убираем кнопку запроса геопозиции
+	_, err := h.Bot.SendMessage(context.Background(), &telego.SendMessageParams{
+		ChatID:      telego.ChatID{ID: chatID},
+		Text:        "📍 Точка получена",
+		ReplyMarkup: &telego.ReplyKeyboardRemove{RemoveKeyboard: true},
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка отправки сообщения: %v", err)
+	}
+	h.SendWithKeyboard(chatID, "📏 Выберите радиус поиска:", nearKeyboard(lat, lon))
+}
+
+// nearKeyboard — кнопки радиуса; точка хранится прямо в callback-данных:
+// "near_<км>_<широта>_<долгота>"
+func nearKeyboard(lat, lon float64) *telego.InlineKeyboardMarkup {
+	var row []telego.InlineKeyboardButton
+	for _, km := range nearRadii {
+		row = append(row, telego.InlineKeyboardButton{
+			Text:         fmt.Sprintf("%d км", km),
+			CallbackData: fmt.Sprintf("near_%d_%.5f_%.5f", km, lat, lon),
+		})
+	}
+	return &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{row}}
+}
+
+// handleNearCallback показывает события в выбранном радиусе, оставляя кнопки для смены радиуса
+func (h *Handlers) handleNearCallback(query *telego.CallbackQuery, payload string) {
+	parts := strings.Split(payload, "_")
+	if len(parts) != 3 {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	radius, errR := strconv.Atoi(parts[0])
+	lat, errLat := strconv.ParseFloat(parts[1], 64)
+	lon, errLon := strconv.ParseFloat(parts[2], 64)
+	if errR != nil || errLat != nil || errLon != nil {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+
+	events, err := h.Services.Events.GetEventsNear(lat, lon, float64(radius))
+	if err != nil {
+		h.answerCallback(query.ID, "Ошибка при поиске событий 😢")
+		return
+	}
+	h.answerCallback(query.ID, "")
+	h.editCallbackPage(query, renderNearbyEvents(events, radius, h.location(query.From.ID)), nearKeyboard(lat, lon))
+}
+
+// renderNearbyEvents — список ближайших событий с расстоянием; даты в поясе зрителя loc
+func renderNearbyEvents(events []models.NearbyEvent, radius int, loc *time.Location) string {
+	if len(events) == 0 {
+		return fmt.Sprintf("😢 В радиусе %d км событий нет. Попробуйте радиус побольше:", radius)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "📍 События в радиусе %d км (ближайшие %d):\n", radius, len(events))
+	for i, event := range events {
+		fmt.Fprintf(&b, "\n%d. %s — %s\n", i+1, event.Title, formatDateTime(event.Date.In(loc)))
+		fmt.Fprintf(&b, "🚶 %s · 📍 %s\n", formatDistance(event.DistanceKm), event.Location)
+		fmt.Fprintf(&b, "🗺 %s\n", mapURL(event.Event))
+		fmt.Fprintf(&b, "Участвовать: /apply_%d\n", event.ID)
+	}
+	return b.String()
+}
@@ -2,16 +2,17 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"log"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/mymmrac/telego"
 	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
 	"tg-bot/internal/service"
 )
 
@@ -22,24 +23,21 @@ type Messenger interface {
 type Handlers struct {
 	Bot      *telego.Bot
 	Services *service.Service
-	states   map[int64]*userState
-	mu       sync.RWMutex
+	states   repository.StateStore
+	admins   map[int64]struct{} // chat ID администраторов бота
 }
 
-type userState struct {
-	step   string
-	event  models.Event
-	chatID int64
-	events []models.Event
-	index  int
-}
-
-func NewHandlers(bot *telego.Bot, s *service.Service) *Handlers {
-	return &Handlers{
+func NewHandlers(bot *telego.Bot, s *service.Service, states repository.StateStore, admins []int64) *Handlers {
+	h := &Handlers{
 		Bot:      bot,
 		Services: s,
-		states:   make(map[int64]*userState),
+		states:   states,
+		admins:   make(map[int64]struct{}, len(admins)),
+	}
+	for _, id := range admins {
+		h.admins[id] = struct{}{}
 	}
+	return h
 }
 
 func (h *Handlers) Run(ctx context.Context) {
@@ -73,44 +71,141 @@ func (h *Handlers) handleUpdate(update telego.Update) {
 			idStr := strings.TrimPrefix(callback, "join_")
 			eventID, err := strconv.ParseInt(idStr, 10, 64)
 			if err != nil {
-				h.Send(chatID, "Неверный ID события")
+				h.answerCallback(update.CallbackQuery.ID, "Неверный ID события")
 				return
 			}
-			// Получаем событие и автора
-			event, err := h.Services.Events.GetByID(eventID)
+			h.answerCallback(update.CallbackQuery.ID, h.requestJoin(chatID, eventID))
+			return
+		}
+		if strings.HasPrefix(callback, "approve_") {
+			h.handleRequestDecision(update.CallbackQuery, strings.TrimPrefix(callback, "approve_"), true)
+			return
+		}
+		if strings.HasPrefix(callback, "reject_") {
+			h.handleRequestDecision(update.CallbackQuery, strings.TrimPrefix(callback, "reject_"), false)
+			return
+		}
+		if strings.HasPrefix(callback, "publish_") || strings.HasPrefix(callback, "draft_") {
+			publish := strings.HasPrefix(callback, "publish_")
+			idStr := callback[strings.Index(callback, "_")+1:]
+			eventID, err := strconv.ParseInt(idStr, 10, 64)
 			if err != nil {
-				h.Send(chatID, "Ошибка при получении события")
+				h.answerCallback(update.CallbackQuery.ID, "Неверный ID события")
 				return
 			}
-			// Получаем автора (используем creator_chat_id)
-			author, err := h.Services.GetUserById(event.CreatorTgID)
+			h.handlePublishCallback(update.CallbackQuery, eventID, publish)
+			return
+		}
+		if strings.HasPrefix(callback, "delete_") {
+			h.handleDeleteCallback(update.CallbackQuery, strings.TrimPrefix(callback, "delete_"))
+			return
+		}
+		if strings.HasPrefix(callback, "edit_") {
+			eventID, err := strconv.ParseInt(strings.TrimPrefix(callback, "edit_"), 10, 64)
 			if err != nil {
-				logrus.Infof("Error getting event author: %s", err)
-				h.Send(chatID, "Ошибка при получении автора события")
+				h.answerCallback(update.CallbackQuery.ID, "Неверный ID события")
 				return
 			}
-			// Получаем пользователя, который хочет присоединиться (используем chatID)
-			user, err := h.Services.GetUserById(chatID)
+			h.answerCallback(update.CallbackQuery.ID, "")
+			h.handleEditCommand(chatID, eventID)
+			return
+		}
+		if strings.HasPrefix(callback, "editfield_") {
+			h.handleEditFieldCallback(update.CallbackQuery, strings.TrimPrefix(callback, "editfield_"))
+			return
+		}
+		if strings.HasPrefix(callback, "remind_off_") {
+			eventID, err := strconv.ParseInt(strings.TrimPrefix(callback, "remind_off_"), 10, 64)
+			if err != nil {
+				h.answerCallback(update.CallbackQuery.ID, "Неверный ID события")
+				return
+			}
+			h.answerCallback(update.CallbackQuery.ID, h.setReminders(chatID, eventID, false))
+			return
+		}
+		if strings.HasPrefix(callback, "setcat_") {
+			categoryID, err := strconv.ParseInt(strings.TrimPrefix(callback, "setcat_"), 10, 64)
 			if err != nil {
-				h.Send(chatID, "Ошибка при получении пользователя")
+				h.answerCallback(update.CallbackQuery.ID, "Неверная категория")
 				return
 			}
-			// Отправляем автору сообщение
-			msg := fmt.Sprintf("Пользователь @%s (id=%d) хочет присоединиться к вашему событию: %s", user.Username, user.ChatID, event.Title)
-			h.Send(author.ChatID, msg)
-			h.Send(chatID, "Запрос на участие отправлен автору события!")
+			h.handleSetCategoryCallback(update.CallbackQuery, categoryID)
+			return
+		}
+		if strings.HasPrefix(callback, "cat_") || strings.HasPrefix(callback, "catpage_") {
+			h.handleCategoryFeedCallback(update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(callback, "tz_") {
+			h.clearState(chatID)
+			reply, _ := h.setTimezone(chatID, strings.TrimPrefix(callback, "tz_"))
+			h.answerCallback(update.CallbackQuery.ID, reply)
+			return
+		}
+		if strings.HasPrefix(callback, "when_") {
+			h.answerCallback(update.CallbackQuery.ID, "")
+			h.clearState(chatID)
+			h.handleDateFilter(chatID, strings.TrimPrefix(callback, "when_"))
+			return
+		}
+		if strings.HasPrefix(callback, "range_") {
+			h.handleRangePageCallback(update.CallbackQuery, strings.TrimPrefix(callback, "range_"))
+			return
+		}
+		if strings.HasPrefix(callback, "members_") {
+			h.handleMembersCallback(update.CallbackQuery, strings.TrimPrefix(callback, "members_"))
+			return
+		}
+		if strings.HasPrefix(callback, "mbrpage_") {
+			h.handleMembersPageCallback(update.CallbackQuery, strings.TrimPrefix(callback, "mbrpage_"))
+			return
+		}
+		if strings.HasPrefix(callback, "mbrall_") {
+			h.handleMembersApproveAllCallback(update.CallbackQuery, strings.TrimPrefix(callback, "mbrall_"))
+			return
+		}
+		if strings.HasPrefix(callback, "mbr_") {
+			h.handleMemberActionCallback(update.CallbackQuery, strings.TrimPrefix(callback, "mbr_"))
+			return
+		}
+		if strings.HasPrefix(callback, "withdraw_") {
+			h.handleWithdrawCallback(update.CallbackQuery, strings.TrimPrefix(callback, "withdraw_"))
+			return
+		}
+		if strings.HasPrefix(callback, "policy_") {
+			h.handleJoinPolicyCallback(update.CallbackQuery, strings.TrimPrefix(callback, "policy_"))
+			return
+		}
+		if callback == "dlq_replay" {
+			h.handleReplayCallback(update.CallbackQuery)
+			return
+		}
+		if strings.HasPrefix(callback, "near_") {
+			h.handleNearCallback(update.CallbackQuery, strings.TrimPrefix(callback, "near_"))
+			return
+		}
+		if strings.HasPrefix(callback, "events_page_") {
+			page, err := strconv.Atoi(strings.TrimPrefix(callback, "events_page_"))
+			if err != nil {
+				h.answerCallback(update.CallbackQuery.ID, "Неверная страница")
+				return
+			}
+			h.answerCallback(update.CallbackQuery.ID, "")
+			h.handleEventsPageCallback(update.CallbackQuery, page)
 			return
 		}
 		if strings.HasPrefix(callback, "next_") {
 			idStr := strings.TrimPrefix(callback, "next_")
 			eventID, err := strconv.ParseInt(idStr, 10, 64)
 			if err != nil {
-				h.Send(chatID, "Неверный ID события")
+				h.answerCallback(update.CallbackQuery.ID, "Неверный ID события")
 				return
 			}
+			h.answerCallback(update.CallbackQuery.ID, "")
 			h.handleNextCommand(chatID, eventID)
 			return
 		}
+		h.answerCallback(update.CallbackQuery.ID, "")
 		return
 	}
 
@@ -141,6 +236,72 @@ func (h *Handlers) handleUpdate(update telego.Update) {
 		return
 	}
 
+	if strings.HasPrefix(text, "/publish_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/publish_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handlePublishCommand(chatID, id)
+		return
+	}
+	if strings.HasPrefix(text, "/close_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/close_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handleCloseCommand(chatID, id)
+		return
+	}
+
+	if strings.HasPrefix(text, "/delete_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/delete_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handleDeleteCommand(chatID, id)
+		return
+	}
+	if strings.HasPrefix(text, "/edit_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/edit_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handleEditCommand(chatID, id)
+		return
+	}
+	if strings.HasPrefix(text, "/invite_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/invite_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handleInviteCommand(chatID, id)
+		return
+	}
+	if strings.HasPrefix(text, "/leave_") {
+		id, err := strconv.ParseInt(strings.TrimPrefix(text, "/leave_"), 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.handleLeaveCommand(chatID, id)
+		return
+	}
+	if strings.HasPrefix(text, "/remind_off_") || strings.HasPrefix(text, "/remind_on_") {
+		enabled := strings.HasPrefix(text, "/remind_on_")
+		id, err := strconv.ParseInt(text[strings.LastIndex(text, "_")+1:], 10, 64)
+		if err != nil {
+			h.Send(chatID, "Неверный ID события")
+			return
+		}
+		h.Send(chatID, h.setReminders(chatID, id, enabled))
+		return
+	}
+
 	switch text {
 	case "/start":
 		h.handleStart(chatID, update.Message.From.Username)
@@ -150,13 +311,35 @@ func (h *Handlers) handleUpdate(update telego.Update) {
 		h.handleEventsCommand(chatID)
 	case "/my_events":
 		h.handleMyEventsCommand(chatID)
+	case "/my_requests":
+		h.handleMyRequestsCommand(chatID)
 	case "/search":
 		h.handleSearchCommand(chatID)
 	case "/random":
 		h.handleRandomCommand(chatID)
+	case "/categories":
+		h.handleCategoriesCommand(chatID)
+	case "/timezone":
+		h.handleTimezoneCommand(chatID)
+	case "/when":
+		h.handleWhenCommand(chatID)
+	case "/today":
+		h.handleDateFilter(chatID, "сегодня")
+	case "/tomorrow":
+		h.handleDateFilter(chatID, "завтра")
+	case "/weekend":
+		h.handleDateFilter(chatID, "выходные")
+	case "/week":
+		h.handleDateFilter(chatID, "неделя")
+	case "/near":
+		h.handleNearCommand(chatID)
+	case "/dlq":
+		h.handleDeadLettersCommand(chatID)
+	case "/dlq_replay":
+		h.handleReplayCommand(chatID)
 
 	default:
-		h.handleUserState(chatID, text)
+		h.handleUserState(chatID, update.Message)
 	}
 }
 func (h *Handlers) handleSearchCommand(chatID int64) {
@@ -166,10 +349,8 @@ func (h *Handlers) handleSearchCommand(chatID int64) {
 		return
 	}
 
-	h.Send(chatID, "🔍 Введите ключевое слово для поиска в названиях событий:")
-	h.mu.Lock()
-	h.states[user.ChatID] = &userState{step: "search_keyword", chatID: user.ChatID} // Сохраняем состояние по chatID
-	h.mu.Unlock()
+	h.Send(chatID, "🔍 Введите запрос — ищем по названию, категории, месту и описанию:")
+	h.setState(user.ChatID, &models.UserState{Step: "search_keyword", ChatID: user.ChatID}) // Сохраняем состояние по chatID
 }
 
 func (h *Handlers) handleRandomCommand(chatID int64) {
@@ -190,10 +371,9 @@ func (h *Handlers) sendRandomEvent(chatID int64) {
 		h.Send(chatID, "Событий нет")
 		return
 	}
-	months := []string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"}
-	msg := fmt.Sprintf("Случайное событие:\nID: %d\nНазвание: %s\nКатегория: %s\nДата: %d %s\nМесто: %s\nСсылка: %s\n",
-		event.ID, event.Title, event.Category, event.Date.Day(), months[event.Date.Month()-1], event.Location, event.URL)
-	h.Send(chatID, msg)
+	msg := fmt.Sprintf("Случайное событие:\nID: %d\nНазвание: %s\nКатегория: %s\nДата: %s\nМесто: %s\nСсылка: %s\n",
+		event.ID, event.Title, event.Category, formatDateTime(event.Date.In(h.location(chatID))), event.Location, event.URL)
+	h.sendEventCard(chatID, event, msg, nil)
 }
 
 func (h *Handlers) handleStart(chatID int64, username string) {
@@ -214,9 +394,7 @@ func (h *Handlers) handleCreateCommand(chatID int64) {
 		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
 		return
 	}
-	h.mu.Lock()
-	h.states[chatID] = &userState{step: "title", chatID: chatID}
-	h.mu.Unlock()
+	h.setState(chatID, &models.UserState{Step: "title", ChatID: chatID})
 	h.Send(chatID, "🎬 Введите название мероприятия:")
 }
 func (h *Handlers) handleMyEventsCommand(chatID int64) {
@@ -252,32 +430,28 @@ func (h *Handlers) handleSearchKeyword(chatID int64, keyword string) {
 		return
 	}
 
-	h.mu.Lock()
-	h.states[user.ChatID] = &userState{
-		step:   "browse_events",
-		chatID: user.ChatID,
-		events: events,
-		index:  0,
-	}
-	h.mu.Unlock()
+	h.setState(user.ChatID, &models.UserState{
+		Step:   "browse_events",
+		ChatID: user.ChatID,
+		Events: events,
+		Index:  0,
+	})
 
 	// Показываем первое событие и устанавливаем индекс
 	h.sendEventByIndex(chatID, 0)
 }
 func (h *Handlers) sendEventByIndex(chatID int64, index int) {
-	h.mu.RLock()
-	state, ok := h.states[chatID]
-	h.mu.RUnlock()
-	if !ok || state == nil || index < 0 || index >= len(state.events) {
+	state := h.getState(chatID)
+	if state == nil || index < 0 || index >= len(state.Events) {
 		h.Send(chatID, "События закончились 🔚")
 		return
 	}
 
-	event := state.events[index]
+	event := state.Events[index]
 	msg := fmt.Sprintf("📌 Событие %d из %d:\n\nНазвание: %s\nКатегория: %s\n📅 Дата: %s\n📍 Место: %s\n🔗 Ссылка: %s",
-		index+1, len(state.events),
+		index+1, len(state.Events),
 		event.Title, event.Category,
-		event.Date.Format("02.01.2006"), event.Location, event.URL)
+		event.Date.In(h.location(chatID)).Format("02.01.2006 15:04"), event.Location, event.URL)
 
 	// Inline-кнопки
 	buttons := [][]telego.InlineKeyboardButton{
@@ -294,50 +468,128 @@ func (h *Handlers) sendEventByIndex(chatID int64, index int) {
 	}
 	keyboard := telego.InlineKeyboardMarkup{InlineKeyboard: buttons}
 
-	// Обновляем текущий индекс безопасно
-	h.mu.Lock()
-	if s, ok := h.states[chatID]; ok && s != nil {
-		s.index = index
-		h.states[chatID] = s
-	}
-	h.mu.Unlock()
+	// Обновляем текущий индекс
+	state.Index = index
+	h.setState(chatID, state)
 
-	_, err := h.Bot.SendMessage(context.Background(), &telego.SendMessageParams{
-		ChatID:      telego.ChatID{ID: chatID},
-		Text:        msg,
-		ReplyMarkup: &keyboard,
-	})
-	if err != nil {
-		logrus.Errorf("Ошибка отправки сообщения с кнопками: %v", err)
-	}
+	h.sendEventCard(chatID, event, msg, &keyboard)
 }
 
 func (h *Handlers) handleApplyCommand(chatID, eventID int64) {
+	h.Send(chatID, h.requestJoin(chatID, eventID))
+}
+
+// requestJoin — общий путь для кнопки «Запросить участие» и команды /apply_<id>.
+// Возвращает текст ответа пользователю.
+func (h *Handlers) requestJoin(chatID, eventID int64) string {
 	if _, err := h.Services.GetUserById(chatID); err != nil {
-		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми"
+	}
+	status, err := h.Services.RequestJoin(eventID, chatID)
+	switch {
+	case errors.Is(err, repository.ErrEventUnavailable):
+		return "Событие не найдено или уже прошло"
+	case errors.Is(err, repository.ErrCreatorCannotJoin):
+		return "Нельзя подать заявку на собственное событие"
+	case errors.Is(err, repository.ErrAlreadyRequested):
+		return "Вы уже отправили заявку на это событие"
+	case errors.Is(err, repository.ErrNotInvited):
+		return "🔒 Это событие только по приглашениям организатора"
+	case err != nil:
+		logrus.Infof("Error applying to event: %s", err)
+		return "Ошибка при отправке заявки 😢"
+	}
+	switch status {
+	case models.ParticipantApproved:
+		return fmt.Sprintf("🎉 Вы участник события ID %d!", eventID)
+	case models.ParticipantWaitlisted:
+		return fmt.Sprintf("⏳ Мест на событие ID %d нет — вы в листе ожидания", eventID)
+	}
+	return fmt.Sprintf("✅ Ваша заявка на участие в событии ID %d отправлена!", eventID)
+}
+
+// handleRequestDecision обрабатывает кнопки «Принять»/«Отклонить» у создателя события.
+// payload имеет вид "<eventID>_<chatID участника>".
+func (h *Handlers) handleRequestDecision(query *telego.CallbackQuery, payload string, approve bool) {
+	creatorChatID := query.From.ID
+	parts := strings.SplitN(payload, "_", 2)
+	if len(parts) != 2 {
+		h.answerCallback(query.ID, "Неверные данные заявки")
 		return
 	}
-	err := h.Services.RequestJoin(eventID, chatID)
+	eventID, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		logrus.Infof("Error applying to event: %s", err)
-		h.Send(chatID, "Ошибка при отправке заявки 😢")
+		h.answerCallback(query.ID, "Неверный ID события")
 		return
 	}
-	h.Send(chatID, fmt.Sprintf("✅ Ваша заявка на участие в событии ID %d отправлена!", eventID))
+	participantChatID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверный ID участника")
+		return
+	}
+
+	status := models.ParticipantRejected
+	if approve {
+		status, err = h.Services.Events.ApproveRequest(eventID, creatorChatID, participantChatID)
+	} else {
+		err = h.Services.Events.RejectRequest(eventID, creatorChatID, participantChatID)
+	}
+	switch {
+	case errors.Is(err, repository.ErrNotEventOwner):
+		h.answerCallback(query.ID, "Только создатель события может принимать решения по заявкам")
+		return
+	case errors.Is(err, repository.ErrRequestNotFound):
+		h.answerCallback(query.ID, "Заявка не найдена или уже обработана")
+		return
+	case err != nil:
+		h.answerCallback(query.ID, "Ошибка при обработке заявки 😢")
+		return
+	}
+
+	decision := "❌ Заявка отклонена"
+	switch status {
+	case models.ParticipantApproved:
+		decision = "✅ Заявка принята"
+	case models.ParticipantWaitlisted:
+		decision = "⏳ Мест нет — участник в листе ожидания"
+	}
+	h.answerCallback(query.ID, decision)
+
+	// Обновляем исходное сообщение создателя: показываем решение и убираем кнопки
+	if query.Message == nil {
+		return
+	}
+	text := "Заявка на участие\n\n" + decision
+	event, errEvent := h.Services.Events.GetByID(eventID)
+	participant, errUser := h.Services.GetUserById(participantChatID)
+	if errEvent != nil || errUser != nil {
+		// Без данных события или участника показываем только решение, а не пустые поля
+		logrus.Infof("Error loading request details: event %v, participant %v", errEvent, errUser)
+	} else {
+		text = fmt.Sprintf("Заявка на участие\n\nСобытие: %s\nОт пользователя: %s\n\n%s",
+			event.Title, models.DisplayName(participant.Username, participant.ChatID), decision)
+	}
+	_, err = h.Bot.EditMessageText(context.Background(), &telego.EditMessageTextParams{
+		ChatID:    telego.ChatID{ID: query.Message.GetChat().ID},
+		MessageID: query.Message.GetMessageID(),
+		Text:      text,
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка редактирования сообщения с заявкой: %v", err)
+	}
 }
+
 func (h *Handlers) handleNextCommand(chatID, eventID int64) {
-	h.mu.RLock()
-	state, ok := h.states[chatID]
-	h.mu.RUnlock()
-	if !ok || state == nil || len(state.events) == 0 {
+	state := h.getState(chatID)
+	if state == nil || len(state.Events) == 0 {
 		h.Send(chatID, "Нет активного поиска. Введите /search чтобы начать снова 🔍")
 		return
 	}
 
 	// Попробуем найти текущий индекс по eventID (кнопка /next_<id>)
-	currentIndex := state.index
+	currentIndex := state.Index
 	found := false
-	for i, ev := range state.events {
+	for i, ev := range state.Events {
 		if ev.ID == eventID {
 			currentIndex = i
 			found = true
@@ -347,119 +599,171 @@ func (h *Handlers) handleNextCommand(chatID, eventID int64) {
 	// если не нашли по id, используем сохранённый индекс
 
 	nextIndex := currentIndex + 1
-	if nextIndex >= len(state.events) {
+	if nextIndex >= len(state.Events) {
 		h.Send(chatID, "Больше событий нет 😢")
-		h.mu.Lock()
-		delete(h.states, chatID)
-		h.mu.Unlock()
+		h.clearState(chatID)
 		return
 	}
 
-	// Обновляем индекс и показываем следующее событие
-	h.mu.Lock()
-	if s, ok := h.states[chatID]; ok && s != nil {
-		s.index = nextIndex
-		h.states[chatID] = s
-	}
-	h.mu.Unlock()
-
+	// Показываем следующее событие (индекс сохраняется в sendEventByIndex)
 	h.sendEventByIndex(chatID, nextIndex)
 
 	// Если не найдено по id и не было активности — ничего дополнительного не делаем
 	_ = found
 }
-func (h *Handlers) handleUserState(chatID int64, text string) {
-	h.mu.RLock()
-	state, ok := h.states[chatID]
-	h.mu.RUnlock()
-	if !ok || state == nil {
+func (h *Handlers) handleUserState(chatID int64, message *telego.Message) {
+	text := message.Text
+	state := h.getState(chatID)
+	if state == nil {
 		return
 	}
 
-	// Блокируем на запись, потому что будем менять состояние
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	// Нужно снова проверить, т.к. состояние могло измениться до блокировки
-	state, ok = h.states[chatID]
-	if !ok || state == nil {
-		return
-	}
-
-	switch state.step {
+	switch state.Step {
 	case "title":
-		state.event.Title = text
-		state.step = "category"
-		h.Send(chatID, "🗂 Введите категорию:")
+		state.Event.Title = text
+		state.Step = "category"
+		h.setState(chatID, state)
+		h.askCategory(chatID, "🗂 Выберите категорию:")
 	case "category":
-		state.event.Category = text
-		state.step = "description"
+		// Категорию можно ввести текстом, но только из каталога
+		category, err := h.Services.FindCategory(text)
+		if err != nil {
+			h.askCategory(chatID, "Такой категории нет, выберите из списка:")
+			return
+		}
+		setEventCategory(&state.Event, category)
+		state.Step = "description"
+		h.setState(chatID, state)
 		h.Send(chatID, "📝 Введите описание:")
 	case "search_keyword":
-		// Сохраняем текущий ключевый запрос временно, но обработка поиска отправит новое состояние
-		// Разблокируем и вызовем обработчик поиска вне lock, чтобы избежать двойной блокировки по h.mu
-		keyword := text
-		delete(h.states, chatID)
-		h.mu.Unlock()
-		h.handleSearchKeyword(chatID, keyword)
-		h.mu.Lock()
+		// Обработка поиска сохранит новое состояние просмотра
+		h.clearState(chatID)
+		h.handleSearchKeyword(chatID, text)
+	case "timezone":
+		h.handleTimezoneInput(chatID, text)
+	case "near_location":
+		h.handleNearLocation(chatID, message)
+	case "date_filter":
+		h.clearState(chatID)
+		h.handleDateFilter(chatID, text)
+	case "edit_value":
+		h.handleEditValue(chatID, state, message)
 	case "choose_action":
 		// Здесь можно обработать выбор действия, например, отправку заявки или просмотр следующего события
 		h.Send(chatID, "Выберите действие: 1. Отправить заявку (/apply_<id>) 2. Следующий ивент (/next_<id>)")
 		// После обработки действия можно удалить состояние пользователя
-		delete(h.states, chatID)
-		return
+		h.clearState(chatID)
 	case "description":
-		state.event.Description = text
-		state.step = "date"
-		h.Send(chatID, "📅 Введите дату (YYYY-MM-DD):")
+		state.Event.Description = text
+		state.Step = "date"
+		h.setState(chatID, state)
+		h.Send(chatID, fmt.Sprintf("📅 Введите дату и время (ДД.ММ.ГГГГ ЧЧ:ММ, например 15.11.2025 19:00), пояс: %s", h.location(chatID)))
 	case "date":
-		parsed, err := time.Parse("2006-01-02", text)
+		parsed, err := parseEventDate(text, time.Now().In(h.location(chatID)))
 		if err != nil {
-			h.Send(chatID, "Неверный формат даты. Попробуйте YYYY-MM-DD")
+			h.Send(chatID, eventDateErrorText(err))
 			return
 		}
-		state.event.Date = parsed
-		state.step = "location"
-		h.Send(chatID, "📍 Укажите место:")
+		state.Event.Date = parsed
+		state.Step = "location"
+		h.setState(chatID, state)
+		h.Send(chatID, "📍 Укажите место текстом или отправьте точку на карте (📎 → Геопозиция / Место):")
 	case "location":
-		state.event.Location = text
-		state.step = "url"
+		if setEventGeo(&state.Event, message) {
+			if state.Event.Location == "" {
+				// Голая геопозиция без названия — просим подписать место
+				state.Step = "location_name"
+				h.setState(chatID, state)
+				h.Send(chatID, "✍️ Точка сохранена. Подпишите место (адрес или ориентир):")
+				return
+			}
+		} else {
+			state.Event.Location = text
+		}
+		state.Step = "url"
+		h.setState(chatID, state)
+		h.Send(chatID, "🔗 Вставьте ссылку на событие (необязательно):")
+	case "location_name":
+		if strings.TrimSpace(text) == "" {
+			h.Send(chatID, "✍️ Подпишите место текстом (адрес или ориентир):")
+			return
+		}
+		state.Event.Location = text
+		state.Step = "url"
+		h.setState(chatID, state)
 		h.Send(chatID, "🔗 Вставьте ссылку на событие (необязательно):")
 	case "url":
-		state.event.URL = text
-		state.event.CreatedAt = time.Now()
-		state.event.UpdatedAt = time.Now()
-		evID, err := h.Services.Events.Create(state.event, chatID)
+		state.Event.URL = text
+		state.Step = "capacity"
+		h.setState(chatID, state)
+		h.Send(chatID, "👥 Сколько максимум участников? Введите число или «-», если без ограничения:")
+	case "capacity":
+		if err := applyEventField(&state.Event, "capacity", strings.TrimSpace(text), h.location(chatID)); err != nil {
+			h.Send(chatID, err.Error())
+			return
+		}
+		state.Step = "join_policy"
+		h.setState(chatID, state)
+		h.askJoinPolicy(chatID)
+	case "join_policy":
+		policy, ok := findJoinPolicy(text)
+		if !ok {
+			h.askJoinPolicy(chatID)
+			return
+		}
+		h.setJoinPolicy(chatID, state, policy)
+	case "invite_usernames":
+		h.handleInviteInput(chatID, state, text)
+	case "image":
+		if photoID := largestPhotoID(message); photoID != "" {
+			state.Event.ImageURL = &photoID
+		} else if text != "-" {
+			if !isHTTPURL(text) {
+				h.Send(chatID, "Отправьте фото, ссылку http(s):// или «-», чтобы пропустить")
+				return
+			}
+			state.Event.ImageURL = &text
+		}
+		state.Event.CreatedAt = time.Now()
+		state.Event.UpdatedAt = time.Now()
+		h.clearState(chatID)
+		evID, err := h.Services.Events.Create(state.Event, chatID)
 		if err != nil {
 			logrus.Infof("Error: %s", err.Error())
 			h.Send(chatID, "Ошибка при создании события")
-			delete(h.states, chatID)
 			return
 		}
-		h.Send(chatID, fmt.Sprintf("✅ Создано! ID: %d, %s — %s", evID, state.event.Title, state.event.Location))
-		delete(h.states, chatID)
+		state.Event.ID = evID
+		state.Event.Status = models.EventDraft
+		h.sendPublishPreview(chatID, state.Event)
+		if state.Event.JoinPolicy == models.JoinInvite {
+			h.Send(chatID, fmt.Sprintf("✉️ Событие только по приглашениям. Пригласить участников: /invite_%d", evID))
+		}
 	}
 }
 
-func (h *Handlers) sendEventsList(chatID int64) {
-	events, err := h.Services.Events.GetEvents()
+// getState читает состояние диалога; при ошибке хранилища считаем, что состояния нет
+func (h *Handlers) getState(chatID int64) *models.UserState {
+	state, err := h.states.Get(chatID)
 	if err != nil {
-		logrus.Infof("Error getting events: %s", err)
-		h.Send(chatID, "Ошибка при получении событий")
-		return
+		logrus.Errorf("Ошибка чтения состояния %d: %v", chatID, err)
+		return nil
 	}
-	if len(events) == 0 {
-		h.Send(chatID, "Событий нет")
-		return
+	return state
+}
+
+func (h *Handlers) setState(chatID int64, state *models.UserState) {
+	if err := h.states.Set(chatID, state); err != nil {
+		logrus.Errorf("Ошибка сохранения состояния %d: %v", chatID, err)
 	}
-	months := []string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"}
-	for i, event := range events {
-		msg := fmt.Sprintf("Событие %d:\nID: %d\nНазвание: %s\nКатегория: %s\nДата:%d %s\nМесто: %s\nСсылка: %s\n",
-			i+1, event.ID, event.Title, event.Category, event.Date.Day(), months[event.Date.Month()-1], event.Location, event.URL)
-		h.Send(chatID, msg)
+}
+
+func (h *Handlers) clearState(chatID int64) {
+	if err := h.states.Delete(chatID); err != nil {
+		logrus.Errorf("Ошибка удаления состояния %d: %v", chatID, err)
 	}
 }
+
 func (h *Handlers) sendMyEventsList(chatID int64) {
 	events, err := h.Services.Events.GetMyEvents(chatID)
 	if err != nil {
@@ -472,10 +776,37 @@ func (h *Handlers) sendMyEventsList(chatID int64) {
 		return
 	}
 	h.Send(chatID, fmt.Sprintf("Ваши события (всего: %d):\n", len(events)))
+	loc := h.location(chatID)
 	for i, event := range events {
-		msg := fmt.Sprintf("Событие %d:\nID: %d\nНазвание: %s\nКатегория: %s\nДата: %s\nМесто: %s\nСсылка: %s\n",
-			i+1, event.ID, event.Title, event.Category, event.Date.Format("02.01.2006"), event.Location, event.URL)
-		h.Send(chatID, msg)
+		msg := fmt.Sprintf("Событие %d:\nID: %d\nНазвание: %s\nКатегория: %s\nДата: %s\nМесто: %s\nСсылка: %s\nСтатус: %s\n",
+			i+1, event.ID, event.Title, event.Category, event.Date.In(loc).Format("02.01.2006 15:04"), event.Location, event.URL, statusTitles[event.Status])
+		h.sendEventCard(chatID, event, msg, myEventKeyboard(event.ID))
+	}
+}
+
+// SendWithKeyboard — отправка сообщения с inline-кнопками
+func (h *Handlers) SendWithKeyboard(chatID int64, text string, keyboard *telego.InlineKeyboardMarkup) {
+	params := &telego.SendMessageParams{
+		ChatID: telego.ChatID{ID: chatID},
+		Text:   text,
+	}
+	if keyboard != nil {
+		params.ReplyMarkup = keyboard
+	}
+	_, err := h.Bot.SendMessage(context.Background(), params)
+	if err != nil {
+		logrus.Errorf("Ошибка отправки сообщения с кнопками: %v", err)
+	}
+}
+
+// answerCallback отвечает на нажатие inline-кнопки всплывающим уведомлением (toast)
+func (h *Handlers) answerCallback(queryID, text string) {
+	err := h.Bot.AnswerCallbackQuery(context.Background(), &telego.AnswerCallbackQueryParams{
+		CallbackQueryID: queryID,
+		Text:            text,
+	})
+	if err != nil {
+		logrus.Errorf("Ошибка ответа на callback: %v", err)
 	}
 }
 
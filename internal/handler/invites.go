@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+	"tg-bot/internal/service"
+)
+
+// joinPolicies — варианты политики вступления в порядке кнопок мастера создания
+var joinPolicies = []struct {
+	key   string
+	title string
+}{
+	{models.JoinManual, "✋ Подтверждаю сам"},
+	{models.JoinAuto, "⚡️ Все сразу"},
+	{models.JoinInvite, "🔒 По приглашениям"},
+}
+
+var joinPolicyTitles = map[string]string{
+	models.JoinManual: "по подтверждению организатора",
+	models.JoinAuto:   "свободное",
+	models.JoinInvite: "только по приглашениям",
+}
+
+// askJoinPolicy показывает кнопки выбора политики вступления; выбор приходит в callback policy_<key>
+func (h *Handlers) askJoinPolicy(chatID int64) {
+	var row []telego.InlineKeyboardButton
+	for _, p := range joinPolicies {
+		row = append(row, telego.InlineKeyboardButton{Text: p.title, CallbackData: "policy_" + p.key})
+	}
+	h.SendWithKeyboard(chatID, "🚪 Кто может присоединиться к событию?", &telego.InlineKeyboardMarkup{
+		InlineKeyboard: [][]telego.InlineKeyboardButton{row},
+	})
+}
+
+// findJoinPolicy сопоставляет ключ или подпись кнопки с политикой
+func findJoinPolicy(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, p := range joinPolicies {
+		if strings.EqualFold(value, p.key) || value == p.title {
+			return p.key, true
+		}
+	}
+	return "", false
+}
+
+// setJoinPolicy сохраняет политику в мастере создания и переходит к шагу афиши
+func (h *Handlers) setJoinPolicy(chatID int64, state *models.UserState, policy string) {
+	state.Event.JoinPolicy = policy
+	state.Step = "image"
+	h.setState(chatID, state)
+	h.Send(chatID, "🖼 Отправьте фото афиши или ссылку на изображение (или «-», чтобы пропустить):")
+}
+
+func (h *Handlers) handleJoinPolicyCallback(query *telego.CallbackQuery, policy string) {
+	chatID := query.From.ID
+	state := h.getState(chatID)
+	if state == nil || state.Step != "join_policy" {
+		h.answerCallback(query.ID, "Мастер создания события не запущен: /create")
+		return
+	}
+	if _, ok := findJoinPolicy(policy); !ok {
+		h.answerCallback(query.ID, "Неизвестный вариант")
+		return
+	}
+	h.answerCallback(query.ID, "")
+	h.editCallbackText(query, "🚪 Вступление: "+joinPolicyTitles[policy])
+	h.setJoinPolicy(chatID, state, policy)
+}
+
+// handleInviteCommand запускает ввод списка приглашённых для события с политикой invite
+func (h *Handlers) handleInviteCommand(chatID, eventID int64) {
+	event, ok := h.getOwnEvent(chatID, eventID)
+	if !ok {
+		return
+	}
+	if event.JoinPolicy != models.JoinInvite {
+		h.Send(chatID, "Приглашения нужны только для событий «по приглашениям»")
+		return
+	}
+	h.setState(chatID, &models.UserState{Step: "invite_usernames", ChatID: chatID, Event: event})
+	h.Send(chatID, "✉️ Перечислите username приглашённых через пробел или запятую, например: @anna @timur")
+}
+
+func (h *Handlers) handleInviteInput(chatID int64, state *models.UserState, text string) {
+	usernames := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n'
+	})
+	added, err := h.Services.Events.AddInvites(state.Event.ID, chatID, usernames)
+	switch {
+	case errors.Is(err, service.ErrInvalidUsername):
+		// Оставляем состояние, чтобы можно было исправить список
+		h.Send(chatID, "Некорректный username: используйте латиницу, цифры и _, от 5 символов. Попробуйте ещё раз:")
+		return
+	case errors.Is(err, repository.ErrNotEventOwner):
+		h.clearState(chatID)
+		h.Send(chatID, "Это действие доступно только создателю события")
+		return
+	case err != nil:
+		h.clearState(chatID)
+		h.Send(chatID, "Ошибка при сохранении приглашений 😢")
+		return
+	}
+	h.clearState(chatID)
+	h.Send(chatID, fmt.Sprintf("✅ Новых приглашений: %d. Приглашённые могут вступить командой /apply_%d", added, state.Event.ID))
+}
@@ -0,0 +1,324 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+)
+
+// requestSections — группы заявок в /my_requests в порядке вывода
+var requestSections = []struct {
+	status string
+	title  string
+}{
+	{models.ParticipantPending, "⏳ На рассмотрении"},
+	{models.ParticipantApproved, "✅ Одобрены"},
+	{models.ParticipantWaitlisted, "🕒 Лист ожидания"},
+	{models.ParticipantRejected, "❌ Отклонены"},
+}
+
+// handleLeaveCommand — участник отказывается от участия или выходит из листа ожидания
+func (h *Handlers) handleLeaveCommand(chatID, eventID int64) {
+	h.Send(chatID, h.leaveEvent(chatID, eventID))
+}
+
+// leaveEvent — общий путь для /leave_<id> и кнопки «Отозвать»; возвращает текст ответа
+func (h *Handlers) leaveEvent(chatID, eventID int64) string {
+	err := h.Services.Events.LeaveEvent(eventID, chatID)
+	switch {
+	case errors.Is(err, repository.ErrRequestNotFound):
+		return "Вы не участвуете в этом событии"
+	case err != nil:
+		return "Ошибка при отказе от участия 😢"
+	}
+	return fmt.Sprintf("👋 Вы больше не участвуете в событии ID %d", eventID)
+}
+
+func (h *Handlers) handleMyRequestsCommand(chatID int64) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	requests, err := h.Services.Events.GetUserRequests(chatID)
+	if err != nil {
+		h.Send(chatID, "Ошибка при получении заявок 😢")
+		return
+	}
+	text, keyboard := renderMyRequests(requests, h.location(chatID))
+	h.SendWithKeyboard(chatID, text, keyboard)
+}
+
+// renderMyRequests группирует заявки по статусу; под списком — кнопки «Отозвать»
+// для всех заявок, кроме отклонённых. Даты выводятся в поясе пользователя loc.
+func renderMyRequests(requests []models.UserRequest, loc *time.Location) (string, *telego.InlineKeyboardMarkup) {
+	if len(requests) == 0 {
+		return "У вас нет заявок на предстоящие события. Найти событие: /events", nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📨 Ваши заявки (всего: %d)\n", len(requests))
+	var rows [][]telego.InlineKeyboardButton
+	for _, section := range requestSections {
+		header := false
+		for _, r := range requests {
+			if r.Status != section.status {
+				continue
+			}
+			if !header {
+				fmt.Fprintf(&b, "\n%s:\n", section.title)
+				header = true
+			}
+			fmt.Fprintf(&b, "• %s — %s, 📍 %s\n", r.Title, formatDateTime(r.Date.In(loc)), r.Location)
+			if r.Status != models.ParticipantRejected {
+				rows = append(rows, []telego.InlineKeyboardButton{{
+					Text:         "↩️ Отозвать: " + truncateRunes(r.Title, 30),
+					CallbackData: fmt.Sprintf("withdraw_%d", r.EventID),
+				}})
+			}
+		}
+	}
+	if len(rows) == 0 {
+		return b.String(), nil
+	}
+	return b.String(), &telego.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// handleWithdrawCallback отзывает заявку и обновляет список /my_requests в том же сообщении
+func (h *Handlers) handleWithdrawCallback(query *telego.CallbackQuery, idStr string) {
+	chatID := query.From.ID
+	eventID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверный ID события")
+		return
+	}
+	h.answerCallback(query.ID, h.leaveEvent(chatID, eventID))
+
+	requests, err := h.Services.Events.GetUserRequests(chatID)
+	if err != nil {
+		logrus.Infof("Error getting user requests: %s", err)
+		return
+	}
+	text, keyboard := renderMyRequests(requests, h.location(chatID))
+	h.editCallbackPage(query, text, keyboard)
+}
+
+// participantStatusTitles — подписи статусов в списке участников для создателя
+var participantStatusTitles = map[string]string{
+	models.ParticipantPending:    "⏳ ждёт решения",
+	models.ParticipantApproved:   "✅ участник",
+	models.ParticipantWaitlisted: "🕒 лист ожидания",
+}
+
+// participantName — @username или chat id, если username не задан
+func participantName(p models.Participant) string {
+	return models.DisplayName(p.Username, p.ChatID)
+}
+
+// renderParticipants — страница участников события с кнопками решений.
+// Callback-данные: mbr_<a|r|x>_<событие>_<участник>_<страница> — принять/отклонить/исключить,
+// mbrpage_<событие>_<страница> — навигация, mbrall_<событие>_<страница> — одобрить все заявки.
+func renderParticipants(event models.Event, page models.ParticipantPage, loc *time.Location) (string, *telego.InlineKeyboardMarkup) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "👥 Участники «%s»\n", event.Title)
+	if event.MaxParticipants != nil {
+		fmt.Fprintf(&b, "Одобрено: %d из %d", page.Approved, *event.MaxParticipants)
+	} else {
+		fmt.Fprintf(&b, "Одобрено: %d", page.Approved)
+	}
+	fmt.Fprintf(&b, " · ждут решения: %d · лист ожидания: %d\n", page.Pending, page.Waitlisted)
+	if page.Total == 0 {
+		b.WriteString("\nЗаявок пока нет")
+		return b.String(), nil
+	}
+	fmt.Fprintf(&b, "Страница %d из %d\n", page.Page+1, page.Pages())
+
+	var rows [][]telego.InlineKeyboardButton
+	for i, p := range page.Participants {
+		name := participantName(p)
+		fmt.Fprintf(&b, "\n%d. %s — %s\n", page.Page*page.PageSize+i+1, name, participantStatusTitles[p.Status])
+		fmt.Fprintf(&b, "   заявка: %s", formatDateTime(p.RequestedAt.In(loc)))
+		if p.ConfirmedAt != nil {
+			fmt.Fprintf(&b, " · решение: %s", formatDateTime(p.ConfirmedAt.In(loc)))
+		}
+		b.WriteString("\n")
+
+		data := func(action string) string {
+			return fmt.Sprintf("mbr_%s_%d_%d_%d", action, event.ID, p.ChatID, page.Page)
+		}
+		if p.Status == models.ParticipantPending {
+			rows = append(rows, []telego.InlineKeyboardButton{
+				{Text: "✅ " + truncateRunes(name, 20), CallbackData: data("a")},
+				{Text: "❌ Отклонить", CallbackData: data("r")},
+			})
+		} else {
+			rows = append(rows, []telego.InlineKeyboardButton{
+				{Text: "🚪 Исключить " + truncateRunes(name, 20), CallbackData: data("x")},
+			})
+		}
+	}
+
+	var nav []telego.InlineKeyboardButton
+	if page.HasPrev() {
+		nav = append(nav, telego.InlineKeyboardButton{Text: "◀️", CallbackData: fmt.Sprintf("mbrpage_%d_%d", event.ID, page.Page-1)})
+	}
+	if page.HasNext() {
+		nav = append(nav, telego.InlineKeyboardButton{Text: "▶️", CallbackData: fmt.Sprintf("mbrpage_%d_%d", event.ID, page.Page+1)})
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	if page.Pending > 0 {
+		rows = append(rows, []telego.InlineKeyboardButton{{
+			Text:         fmt.Sprintf("✅ Одобрить все заявки (%d)", page.Pending),
+			CallbackData: fmt.Sprintf("mbrall_%d_%d", event.ID, page.Page),
+		}})
+	}
+	return b.String(), &telego.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// participantsErrorText — текст ошибки действий со списком участников
+func participantsErrorText(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrNotEventOwner):
+		return "Это действие доступно только создателю события"
+	case errors.Is(err, repository.ErrRequestNotFound):
+		return "Заявка не найдена или уже обработана"
+	case errors.Is(err, repository.ErrEventNotFound):
+		return "Событие не найдено"
+	}
+	return "Ошибка при обработке заявки 😢"
+}
+
+// parseIDs разбирает payload вида "<int>_<int>_..." ровно из n чисел
+func parseIDs(payload string, n int) ([]int64, bool) {
+	parts := strings.Split(payload, "_")
+	if len(parts) != n {
+		return nil, false
+	}
+	ids := make([]int64, n)
+	for i, part := range parts {
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		ids[i] = id
+	}
+	return ids, true
+}
+
+// loadParticipants собирает страницу участников для создателя chatID
+func (h *Handlers) loadParticipants(chatID, eventID int64, page int) (string, *telego.InlineKeyboardMarkup, error) {
+	participants, err := h.Services.Events.GetEventParticipants(eventID, chatID, page)
+	if err != nil {
+		return "", nil, err
+	}
+	event, err := h.Services.Events.GetByID(eventID)
+	if err != nil {
+		return "", nil, err
+	}
+	text, keyboard := renderParticipants(event, participants, h.location(chatID))
+	return text, keyboard, nil
+}
+
+// handleMembersCallback — кнопка «Участники» под карточкой: список приходит отдельным сообщением,
+// т.к. карточка может быть фото. payload: "<событие>".
+func (h *Handlers) handleMembersCallback(query *telego.CallbackQuery, payload string) {
+	eventID, err := strconv.ParseInt(payload, 10, 64)
+	if err != nil {
+		h.answerCallback(query.ID, "Неверный ID события")
+		return
+	}
+	text, keyboard, err := h.loadParticipants(query.From.ID, eventID, 0)
+	if err != nil {
+		h.answerCallback(query.ID, participantsErrorText(err))
+		return
+	}
+	h.answerCallback(query.ID, "")
+	h.SendWithKeyboard(query.From.ID, text, keyboard)
+}
+
+// handleMembersPageCallback перелистывает список участников. payload: "<событие>_<страница>".
+func (h *Handlers) handleMembersPageCallback(query *telego.CallbackQuery, payload string) {
+	ids, ok := parseIDs(payload, 2)
+	if !ok {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	h.refreshParticipants(query, ids[0], int(ids[1]), "")
+}
+
+// refreshParticipants отвечает на callback текстом toast и перерисовывает список на странице page
+func (h *Handlers) refreshParticipants(query *telego.CallbackQuery, eventID int64, page int, toast string) {
+	text, keyboard, err := h.loadParticipants(query.From.ID, eventID, page)
+	if err != nil {
+		h.answerCallback(query.ID, participantsErrorText(err))
+		return
+	}
+	h.answerCallback(query.ID, toast)
+	h.editCallbackPage(query, text, keyboard)
+}
+
+// handleMemberActionCallback принимает, отклоняет или исключает участника из списка.
+// payload: "<a|r|x>_<событие>_<участник>_<страница>".
+func (h *Handlers) handleMemberActionCallback(query *telego.CallbackQuery, payload string) {
+	action, rest, _ := strings.Cut(payload, "_")
+	ids, ok := parseIDs(rest, 3)
+	if !ok {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	creatorChatID := query.From.ID
+	eventID, participantChatID, page := ids[0], ids[1], int(ids[2])
+
+	var toast string
+	var err error
+	switch action {
+	case "a":
+		var status string
+		status, err = h.Services.Events.ApproveRequest(eventID, creatorChatID, participantChatID)
+		toast = "✅ Заявка принята"
+		if status == models.ParticipantWaitlisted {
+			toast = "⏳ Мест нет — участник в листе ожидания"
+		}
+	case "r":
+		err = h.Services.Events.RejectRequest(eventID, creatorChatID, participantChatID)
+		toast = "❌ Заявка отклонена"
+	case "x":
+		err = h.Services.Events.RemoveParticipant(eventID, creatorChatID, participantChatID)
+		toast = "🚪 Участник исключён"
+	default:
+		h.answerCallback(query.ID, "Неизвестное действие")
+		return
+	}
+	if err != nil {
+		h.answerCallback(query.ID, participantsErrorText(err))
+		return
+	}
+	h.refreshParticipants(query, eventID, page, toast)
+}
+
+// handleMembersApproveAllCallback одобряет все ожидающие заявки. payload: "<событие>_<страница>".
+func (h *Handlers) handleMembersApproveAllCallback(query *telego.CallbackQuery, payload string) {
+	ids, ok := parseIDs(payload, 2)
+	if !ok {
+		h.answerCallback(query.ID, "Неверные данные")
+		return
+	}
+	approved, waitlisted, err := h.Services.Events.ApproveAllPending(ids[0], query.From.ID)
+	if err != nil {
+		h.answerCallback(query.ID, participantsErrorText(err))
+		return
+	}
+	toast := fmt.Sprintf("✅ Одобрено: %d", approved)
+	if waitlisted > 0 {
+		toast += fmt.Sprintf(", в листе ожидания: %d", waitlisted)
+	}
+	h.refreshParticipants(query, ids[0], int(ids[1]), toast)
+}
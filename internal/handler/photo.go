@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+)
+
+// maxCaptionLen — ограничение Telegram на длину подписи к фото
+const maxCaptionLen = 1024
+
+// eventPhoto возвращает афишу события: image_url хранит либо file_id из Telegram, либо http(s)-ссылку
+func eventPhoto(event models.Event) (telego.InputFile, bool) {
+	if event.ImageURL == nil || *event.ImageURL == "" {
+		return telego.InputFile{}, false
+	}
+	if isHTTPURL(*event.ImageURL) {
+		return telego.InputFile{URL: *event.ImageURL}, true
+	}
+	return telego.InputFile{FileID: *event.ImageURL}, true
+}
+
+// largestPhotoID — file_id самого большого размера присланного фото
+func largestPhotoID(message *telego.Message) string {
+	if message == nil || len(message.Photo) == 0 {
+		return ""
+	}
+	return message.Photo[len(message.Photo)-1].FileID
+}
+
+// sendEventCard отправляет карточку события: фото с подписью, если есть афиша, иначе текст
+func (h *Handlers) sendEventCard(chatID int64, event models.Event, text string, keyboard *telego.InlineKeyboardMarkup) {
+	photo, ok := eventPhoto(event)
+	if !ok {
+		h.SendWithKeyboard(chatID, text, keyboard)
+		return
+	}
+	params := &telego.SendPhotoParams{
+		ChatID:  telego.ChatID{ID: chatID},
+		Photo:   photo,
+		Caption: truncateRunes(text, maxCaptionLen),
+	}
+	if keyboard != nil {
+		params.ReplyMarkup = keyboard
+	}
+	if _, err := h.Bot.SendPhoto(context.Background(), params); err != nil {
+		// Битая ссылка или устаревший file_id не должны скрывать само событие
+		logrus.Errorf("Ошибка отправки афиши события %d: %v", event.ID, err)
+		h.SendWithKeyboard(chatID, text, keyboard)
+	}
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
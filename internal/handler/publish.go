@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+)
+
+var statusTitles = map[string]string{
+	models.EventDraft:     "📝 черновик",
+	models.EventPublished: "🟢 опубликовано",
+	models.EventClosed:    "🔒 закрыто",
+}
+
+// formatEventCard — карточка события для предпросмотра перед публикацией; дата в поясе loc
+func formatEventCard(event models.Event, loc *time.Location) string {
+	card := fmt.Sprintf("ID: %d\nНазвание: %s\nКатегория: %s\n📝 %s\n📅 Дата: %s\n📍 Место: %s\n🔗 Ссылка: %s\nСтатус: %s",
+		event.ID, event.Title, event.Category, event.Description,
+		event.Date.In(loc).Format("02.01.2006 15:04"), event.Location, event.URL, statusTitles[event.Status])
+	if event.HasGeo() {
+		card += "\n🗺 На карте: " + mapURL(event)
+	}
+	if event.MaxParticipants != nil {
+		card += fmt.Sprintf("\n👥 Мест: %d", *event.MaxParticipants)
+	}
+	if title, ok := joinPolicyTitles[event.JoinPolicy]; ok {
+		card += "\n🚪 Вступление: " + title
+	}
+	return card
+}
+
+// sendPublishPreview показывает созданный черновик с кнопками публикации
+func (h *Handlers) sendPublishPreview(chatID int64, event models.Event) {
+	keyboard := telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{
+		{
+			{Text: "🚀 Опубликовать", CallbackData: fmt.Sprintf("publish_%d", event.ID)},
+			{Text: "📝 Оставить черновиком", CallbackData: fmt.Sprintf("draft_%d", event.ID)},
+		},
+	}}
+	h.sendEventCard(chatID, event, "👀 Предпросмотр события:\n\n"+formatEventCard(event, h.location(chatID)), &keyboard)
+}
+
+// handlePublishCallback обрабатывает кнопки предпросмотра «Опубликовать» / «Оставить черновиком»
+func (h *Handlers) handlePublishCallback(query *telego.CallbackQuery, eventID int64, publish bool) {
+	chatID := query.From.ID
+	text := fmt.Sprintf("📝 Черновик сохранён. Опубликовать позже: /publish_%d", eventID)
+	if publish {
+		if err := h.Services.Events.PublishEvent(eventID, chatID); err != nil {
+			h.answerCallback(query.ID, statusErrorText(err))
+			return
+		}
+		text = fmt.Sprintf("🚀 Событие опубликовано! Закрыть набор: /close_%d", eventID)
+	}
+	h.answerCallback(query.ID, "")
+
+	// Убираем кнопки с предпросмотра, чтобы их не нажали повторно
+	if query.Message != nil {
+		_, err := h.Bot.EditMessageReplyMarkup(context.Background(), &telego.EditMessageReplyMarkupParams{
+			ChatID:    telego.ChatID{ID: query.Message.GetChat().ID},
+			MessageID: query.Message.GetMessageID(),
+		})
+		if err != nil {
+			logrus.Errorf("Ошибка редактирования предпросмотра: %v", err)
+		}
+	}
+	h.Send(chatID, text)
+}
+
+func (h *Handlers) handlePublishCommand(chatID, eventID int64) {
+	if err := h.Services.Events.PublishEvent(eventID, chatID); err != nil {
+		h.Send(chatID, statusErrorText(err))
+		return
+	}
+	h.Send(chatID, fmt.Sprintf("🚀 Событие ID %d опубликовано!", eventID))
+}
+
+func (h *Handlers) handleCloseCommand(chatID, eventID int64) {
+	if err := h.Services.Events.CloseEvent(eventID, chatID); err != nil {
+		h.Send(chatID, statusErrorText(err))
+		return
+	}
+	h.Send(chatID, fmt.Sprintf("🔒 Событие ID %d закрыто", eventID))
+}
+
+// statusErrorText переводит ошибку смены статуса в сообщение пользователю
+func statusErrorText(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrEventNotFound):
+		return "Событие не найдено"
+	case errors.Is(err, repository.ErrNotEventOwner):
+		return "Это действие доступно только создателю события"
+	case errors.Is(err, repository.ErrInvalidEventStatus):
+		return "Текущий статус события не позволяет это действие"
+	default:
+		return "Ошибка при изменении статуса события 😢"
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"tg-bot/internal/repository"
+)
+
+// setReminders включает/отключает напоминания по событию и возвращает текст ответа
+func (h *Handlers) setReminders(chatID, eventID int64, enabled bool) string {
+	err := h.Services.Reminders.SetReminders(eventID, chatID, enabled)
+	switch {
+	case errors.Is(err, repository.ErrRequestNotFound):
+		return "Вы не участвуете в этом событии"
+	case err != nil:
+		return "Ошибка при изменении напоминаний 😢"
+	}
+	if enabled {
+		return fmt.Sprintf("🔔 Напоминания о событии ID %d включены", eventID)
+	}
+	return fmt.Sprintf("🔕 Напоминания о событии ID %d отключены. Включить снова: /remind_on_%d", eventID, eventID)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mymmrac/telego"
+	"tg-bot/internal/models"
+	"tg-bot/internal/service"
+)
+
+// popularTimezones — быстрый выбор часового пояса кнопками
+var popularTimezones = []struct {
+	title string
+	name  string
+}{
+	{"Алматы / Астана", "Asia/Almaty"},
+	{"Ташкент", "Asia/Tashkent"},
+	{"Бишкек", "Asia/Bishkek"},
+	{"Москва", "Europe/Moscow"},
+	{"Стамбул", "Europe/Istanbul"},
+	{"UTC", "UTC"},
+}
+
+var utcOffsetRe = regexp.MustCompile(`^(?:utc|gmt)?\s*([+-])(\d{1,2})$`)
+
+// location — часовой пояс пользователя для вывода и ввода дат
+func (h *Handlers) location(chatID int64) *time.Location {
+	return h.Services.GetLocation(chatID)
+}
+
+func (h *Handlers) handleTimezoneCommand(chatID int64) {
+	if _, err := h.Services.GetUserById(chatID); err != nil {
+		h.Send(chatID, "Привет, Гость! Тебе нужно зарегистрироваться! \n /start <- Нажми")
+		return
+	}
+	var rows [][]telego.InlineKeyboardButton
+	for i, tz := range popularTimezones {
+		button := telego.InlineKeyboardButton{Text: tz.title, CallbackData: "tz_" + tz.name}
+		if i%2 == 0 {
+			rows = append(rows, []telego.InlineKeyboardButton{button})
+		} else {
+			rows[len(rows)-1] = append(rows[len(rows)-1], button)
+		}
+	}
+	h.setState(chatID, &models.UserState{Step: "timezone", ChatID: chatID})
+	text := fmt.Sprintf("🕒 Ваш часовой пояс: %s\n\nВыберите новый кнопкой или напишите его: «Europe/Berlin» или смещение «UTC+5»",
+		h.location(chatID))
+	h.SendWithKeyboard(chatID, text, &telego.InlineKeyboardMarkup{InlineKeyboard: rows})
+}
+
+func (h *Handlers) handleTimezoneInput(chatID int64, text string) {
+	name := strings.TrimSpace(text)
+	// Смещение «UTC+5» переводим в IANA-зону Etc/GMT-5 (в Etc/GMT знак инвертирован)
+	if m := utcOffsetRe.FindStringSubmatch(strings.ToLower(name)); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		sign := "-"
+		if m[1] == "-" {
+			sign = "+"
+		}
+		name = "Etc/GMT" + sign + strconv.Itoa(hours)
+		if hours == 0 {
+			name = "UTC"
+		}
+	}
+	reply, ok := h.setTimezone(chatID, name)
+	if ok {
+		h.clearState(chatID)
+	}
+	h.Send(chatID, reply)
+}
+
+// setTimezone сохраняет пояс и возвращает текст ответа; ok — пояс сохранён
+func (h *Handlers) setTimezone(chatID int64, name string) (reply string, ok bool) {
+	loc, err := h.Services.SetTimezone(chatID, name)
+	switch {
+	case errors.Is(err, service.ErrInvalidTimezone):
+		return "Не знаю такой часовой пояс 🤔 Пример: Asia/Almaty или UTC+5", false
+	case err != nil:
+		return "Ошибка при сохранении часового пояса 😢", false
+	}
+	return fmt.Sprintf("✅ Часовой пояс: %s (сейчас %s)", loc, time.Now().In(loc).Format("15:04")), true
+}
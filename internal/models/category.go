@@ -0,0 +1,8 @@
+package models
+
+type Category struct {
+	ID    int64  `db:"id"`
+	Title string `db:"title"`
+	// EventCount — количество опубликованных предстоящих событий в категории
+	EventCount int `db:"event_count"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// DeadLetter — сообщение, которое потребитель так и не смог обработать
+type DeadLetter struct {
+	Queue     string // очередь потребителя, из которой сообщение ушло в DLQ
+	Type      string // тип доменного события, если конверт удалось разобрать
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+	Body      []byte
+}
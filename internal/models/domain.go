@@ -0,0 +1,174 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EnvelopeVersion — текущая версия схемы конверта доменных событий
+const EnvelopeVersion = 1
+
+// EventsExchange — topic-обменник доменных событий; ключ маршрутизации равен Envelope.Type,
+// поэтому компоненты подписывают свои очереди на нужные типы (например, "participant.*")
+const EventsExchange = "kaidabaram.events"
+
+// Типы доменных событий (Envelope.Type)
+const (
+	DomainUserCreated          = "user.created"
+	DomainEventCreated         = "event.created"
+	DomainParticipantRequested = "participant.requested"
+	DomainParticipantApproved  = "participant.approved"
+)
+
+// ErrInvalidDomainEvent — сообщение не соответствует схеме: повторная обработка не поможет
+var ErrInvalidDomainEvent = errors.New("invalid domain event")
+
+// DomainEvent — типизированная полезная нагрузка доменного события
+type DomainEvent interface {
+	EventType() string
+	Validate() error
+}
+
+// Envelope — конверт доменного события в очереди: метаданные и payload конкретного типа
+type Envelope struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Version     int             `json:"version"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	ActorChatID int64           `json:"actor_chat_id"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// UserCreated — пользователь впервые зарегистрировался через /start
+type UserCreated struct {
+	UserID   int64  `json:"user_id"`
+	ChatID   int64  `json:"chat_id"`
+	Username string `json:"username"`
+}
+
+// EventCreated — создан черновик события
+type EventCreated struct {
+	EventID    int64  `json:"event_id"`
+	Title      string `json:"title"`
+	CategoryID *int64 `json:"category_id,omitempty"`
+	JoinPolicy string `json:"join_policy"`
+}
+
+// JoinRequested — подана заявка на участие; Status — её статус сразу после подачи
+type JoinRequested struct {
+	EventID int64  `json:"event_id"`
+	ChatID  int64  `json:"chat_id"`
+	Status  string `json:"status"`
+}
+
+// JoinApproved — участник одобрен: создателем, автоматически или из листа ожидания
+type JoinApproved struct {
+	EventID int64 `json:"event_id"`
+	ChatID  int64 `json:"chat_id"`
+}
+
+func (UserCreated) EventType() string   { return DomainUserCreated }
+func (EventCreated) EventType() string  { return DomainEventCreated }
+func (JoinRequested) EventType() string { return DomainParticipantRequested }
+func (JoinApproved) EventType() string  { return DomainParticipantApproved }
+
+func (e UserCreated) Validate() error {
+	if e.UserID <= 0 || e.ChatID == 0 {
+		return fmt.Errorf("%s: user_id and chat_id are required: %w", DomainUserCreated, ErrInvalidDomainEvent)
+	}
+	return nil
+}
+
+func (e EventCreated) Validate() error {
+	if e.EventID <= 0 {
+		return fmt.Errorf("%s: event_id is required: %w", DomainEventCreated, ErrInvalidDomainEvent)
+	}
+	return nil
+}
+
+func (e JoinRequested) Validate() error {
+	if e.EventID <= 0 || e.ChatID == 0 {
+		return fmt.Errorf("%s: event_id and chat_id are required: %w", DomainParticipantRequested, ErrInvalidDomainEvent)
+	}
+	switch e.Status {
+	case ParticipantPending, ParticipantApproved, ParticipantWaitlisted:
+		return nil
+	}
+	return fmt.Errorf("%s: unknown status %q: %w", DomainParticipantRequested, e.Status, ErrInvalidDomainEvent)
+}
+
+func (e JoinApproved) Validate() error {
+	if e.EventID <= 0 || e.ChatID == 0 {
+		return fmt.Errorf("%s: event_id and chat_id are required: %w", DomainParticipantApproved, ErrInvalidDomainEvent)
+	}
+	return nil
+}
+
+// domainEventTypes — фабрики payload по типу события для разбора входящих сообщений
+var domainEventTypes = map[string]func() DomainEvent{
+	DomainUserCreated:          func() DomainEvent { return &UserCreated{} },
+	DomainEventCreated:         func() DomainEvent { return &EventCreated{} },
+	DomainParticipantRequested: func() DomainEvent { return &JoinRequested{} },
+	DomainParticipantApproved:  func() DomainEvent { return &JoinApproved{} },
+}
+
+// NewEnvelope упаковывает событие в конверт текущей версии со случайным ID
+func NewEnvelope(event DomainEvent, actorChatID int64) (Envelope, error) {
+	if err := event.Validate(); err != nil {
+		return Envelope{}, err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		ID:          newEventID(),
+		Type:        event.EventType(),
+		Version:     EnvelopeVersion,
+		OccurredAt:  time.Now().UTC(),
+		ActorChatID: actorChatID,
+		Payload:     payload,
+	}, nil
+}
+
+// DecodeEnvelope разбирает и проверяет сообщение из очереди и возвращает типизированный payload.
+// Все ошибки схемы оборачивают ErrInvalidDomainEvent.
+func DecodeEnvelope(body []byte) (Envelope, DomainEvent, error) {
+	var env Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return Envelope{}, nil, fmt.Errorf("unmarshal envelope: %v: %w", err, ErrInvalidDomainEvent)
+	}
+	switch {
+	case env.ID == "":
+		return env, nil, fmt.Errorf("envelope id is empty: %w", ErrInvalidDomainEvent)
+	case env.Version != EnvelopeVersion:
+		return env, nil, fmt.Errorf("envelope %s: unsupported version %d: %w", env.ID, env.Version, ErrInvalidDomainEvent)
+	case env.OccurredAt.IsZero():
+		return env, nil, fmt.Errorf("envelope %s: occurred_at is empty: %w", env.ID, ErrInvalidDomainEvent)
+	}
+
+	factory, ok := domainEventTypes[env.Type]
+	if !ok {
+		return env, nil, fmt.Errorf("envelope %s: unknown type %q: %w", env.ID, env.Type, ErrInvalidDomainEvent)
+	}
+	event := factory()
+	if err := json.Unmarshal(env.Payload, event); err != nil {
+		return env, nil, fmt.Errorf("envelope %s: unmarshal %s payload: %v: %w", env.ID, env.Type, err, ErrInvalidDomainEvent)
+	}
+	if err := event.Validate(); err != nil {
+		return env, nil, fmt.Errorf("envelope %s: %w", env.ID, err)
+	}
+	return env, event, nil
+}
+
+// newEventID — случайный UUID v4
+func newEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
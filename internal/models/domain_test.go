@@ -0,0 +1,97 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestDecodeEnvelope(t *testing.T) {
+	valid := `{"id":"e1","type":"participant.approved","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+		`"actor_chat_id":7,"payload":{"event_id":3,"chat_id":5}}`
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"valid", valid, false},
+		{"not json", `not json`, true},
+		{"json null", `null`, true},
+		{"json array", `[]`, true},
+		{"empty object", `{}`, true},
+		{"legacy map format", `{"event":"join_approved","event_id":3,"chat_id":5}`, true},
+		{"empty id", `{"id":"","type":"participant.approved","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":3,"chat_id":5}}`, true},
+		{"missing occurred_at", `{"id":"e1","type":"participant.approved","version":1,` +
+			`"payload":{"event_id":3,"chat_id":5}}`, true},
+		{"wrong version", `{"id":"e1","type":"participant.approved","version":2,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":3,"chat_id":5}}`, true},
+		{"missing version", `{"id":"e1","type":"participant.approved","occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":3,"chat_id":5}}`, true},
+		{"unknown type", `{"id":"e1","type":"event.exploded","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{}}`, true},
+		{"missing payload", `{"id":"e1","type":"participant.approved","version":1,"occurred_at":"2025-11-15T10:00:00Z"}`, true},
+		{"payload of wrong shape", `{"id":"e1","type":"participant.approved","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":"three"}}`, true},
+		{"payload fails validate", `{"id":"e1","type":"participant.approved","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":3}}`, true},
+		{"unknown request status", `{"id":"e1","type":"participant.requested","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"event_id":3,"chat_id":5,"status":"rejected"}}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, event, err := DecodeEnvelope([]byte(tt.body))
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				approved, ok := event.(*JoinApproved)
+				if !ok || approved.EventID != 3 || approved.ChatID != 5 || env.ActorChatID != 7 {
+					t.Fatalf("decoded %+v / %+v", env, event)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidDomainEvent) {
+				t.Fatalf("want ErrInvalidDomainEvent, got %v", err)
+			}
+			if event != nil {
+				t.Fatalf("want nil event on error, got %+v", event)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeRoundTrip(t *testing.T) {
+	events := []DomainEvent{
+		UserCreated{UserID: 1, ChatID: 2, Username: "alice"},
+		EventCreated{EventID: 3, Title: "Кино", JoinPolicy: JoinAuto},
+		JoinRequested{EventID: 3, ChatID: 2, Status: ParticipantWaitlisted},
+		JoinApproved{EventID: 3, ChatID: 2},
+	}
+	for _, event := range events {
+		t.Run(event.EventType(), func(t *testing.T) {
+			env, err := NewEnvelope(event, 2)
+			if err != nil {
+				t.Fatalf("NewEnvelope: %v", err)
+			}
+			body, err := json.Marshal(env)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			decoded, got, err := DecodeEnvelope(body)
+			if err != nil {
+				t.Fatalf("DecodeEnvelope: %v", err)
+			}
+			if decoded.ID != env.ID || decoded.Type != event.EventType() || got.EventType() != event.EventType() {
+				t.Fatalf("round trip mismatch: %+v -> %+v", env, decoded)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeRejectsInvalidEvent(t *testing.T) {
+	if _, err := NewEnvelope(JoinApproved{EventID: 3}, 0); !errors.Is(err, ErrInvalidDomainEvent) {
+		t.Fatalf("want ErrInvalidDomainEvent, got %v", err)
+	}
+}
@@ -2,18 +2,67 @@ package models
 
 import "time"
 
+// Статусы события (events.status)
+const (
+	EventDraft     = "draft"
+	EventPublished = "published"
+	EventClosed    = "closed"
+)
+
+// Политики вступления в событие (events.join_policy)
+const (
+	JoinManual = "manual" // создатель подтверждает каждую заявку
+	JoinAuto   = "auto"   // заявки одобряются сразу
+	JoinInvite = "invite" // вступить могут только приглашённые
+)
+
+// EventPage — страница списка событий (Page считается с нуля)
+type EventPage struct {
+	Events   []Event
+	Page     int
+	PageSize int
+	Total    int
+}
+
+// Pages — общее количество страниц
+func (p EventPage) Pages() int {
+	if p.PageSize <= 0 || p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+func (p EventPage) HasPrev() bool { return p.Page > 0 }
+func (p EventPage) HasNext() bool { return p.Page+1 < p.Pages() }
+
+// NearbyEvent — событие с расстоянием до точки пользователя в километрах
+type NearbyEvent struct {
+	Event
+	DistanceKm float64 `db:"distance_km"`
+}
+
 type Event struct {
-	ID          int64     `db:"id"`
-	Title       string    `db:"title"`
-	Category    string    `db:"category"`
-	Date        time.Time `db:"date"`
-	Location    string    `db:"location"`
-	Description string    `db:"description"`
-	URL         string    `db:"url"`
-	ImageURL    *string   `db:"image_url"`
-	CreatorTgID int64     `db:"creator_telegram_id"`
-	CreatorID   int64     `db:"creator_id"`
-	Status      string    `db:"status"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID              int64     `db:"id"`
+	Title           string    `db:"title"`
+	Category        string    `db:"category"`
+	CategoryID      *int64    `db:"category_id"`
+	Date            time.Time `db:"date"`
+	Location        string    `db:"location"`
+	Latitude        *float64  `db:"latitude"`
+	Longitude       *float64  `db:"longitude"`
+	Description     string    `db:"description"`
+	URL             string    `db:"url"`
+	ImageURL        *string   `db:"image_url"`
+	MaxParticipants *int      `db:"max_participants"`
+	JoinPolicy      string    `db:"join_policy"`
+	CreatorTgID     int64     `db:"creator_telegram_id"`
+	CreatorID       int64     `db:"creator_id"`
+	Status          string    `db:"status"`
+	CreatedAt       time.Time `db:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
+}
+
+// HasGeo — указаны ли координаты места
+func (e Event) HasGeo() bool {
+	return e.Latitude != nil && e.Longitude != nil
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// OutboxMessage — доменное событие в конверте (Envelope), ожидающее отправки в брокер
+type OutboxMessage struct {
+	ID        int64     `db:"id"`
+	Exchange  string    `db:"exchange"`
+	EventType string    `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	Attempts  int       `db:"attempts"`
+	CreatedAt time.Time `db:"created_at"`
+}
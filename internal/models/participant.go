@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Статусы заявки на участие (event_participants.status)
+const (
+	ParticipantPending  = "pending"
+	ParticipantApproved = "approved"
+	ParticipantRejected = "rejected"
+	// ParticipantWaitlisted — заявка одобрена, но мест нет; ждёт освободившегося места
+	ParticipantWaitlisted = "waitlisted"
+)
+
+// Participant — заявка на участие вместе с данными пользователя
+type Participant struct {
+	ID          int64      `db:"id"`
+	EventID     int64      `db:"event_id"`
+	UserID      int64      `db:"user_id"`
+	ChatID      int64      `db:"chat_id"`
+	Username    string     `db:"username"`
+	Timezone    *string    `db:"timezone"`
+	Status      string     `db:"status"`
+	RequestedAt time.Time  `db:"requested_at"`
+	ConfirmedAt *time.Time `db:"confirmed_at"`
+}
+
+// ParticipantPage — страница списка участников события для создателя (Page считается с нуля).
+// Pending, Approved и Waitlisted — количество заявок в этих статусах по всему событию.
+type ParticipantPage struct {
+	Participants []Participant
+	Page         int
+	PageSize     int
+	Total        int
+	Pending      int
+	Approved     int
+	Waitlisted   int
+}
+
+// Pages — общее количество страниц
+func (p ParticipantPage) Pages() int {
+	if p.PageSize <= 0 || p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+func (p ParticipantPage) HasPrev() bool { return p.Page > 0 }
+func (p ParticipantPage) HasNext() bool { return p.Page+1 < p.Pages() }
+
+// JoinDigestEntry — автоматически одобренная заявка, ещё не попавшая в сводку создателю
+type JoinDigestEntry struct {
+	EventID       int64  `db:"event_id"`
+	EventTitle    string `db:"event_title"`
+	CreatorChatID int64  `db:"creator_chat_id"`
+	ChatID        int64  `db:"chat_id"`
+	Username      string `db:"username"`
+	Status        string `db:"status"`
+}
+
+// DisplayName — «@username» или «id<chat>», если у пользователя нет username
+func DisplayName(username string, chatID int64) string {
+	if username == "" {
+		return fmt.Sprintf("id%d", chatID)
+	}
+	return "@" + username
+}
+
+// UserRequest — заявка пользователя вместе с данными события, на которое она подана
+type UserRequest struct {
+	EventID     int64      `db:"event_id"`
+	Title       string     `db:"title"`
+	Date        time.Time  `db:"date"`
+	Location    string     `db:"location"`
+	Status      string     `db:"status"`
+	RequestedAt time.Time  `db:"requested_at"`
+	ConfirmedAt *time.Time `db:"confirmed_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Reminder — напоминание одобренному участнику о скором начале события
+type Reminder struct {
+	EventID  int64     `db:"event_id"`
+	UserID   int64     `db:"user_id"`
+	ChatID   int64     `db:"chat_id"`
+	Timezone *string   `db:"timezone"`
+	Title    string    `db:"title"`
+	Date     time.Time `db:"date"`
+	Location string    `db:"location"`
+}
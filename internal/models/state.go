@@ -0,0 +1,13 @@
+package models
+
+// UserState — состояние диалога пользователя (мастер /create, просмотр результатов поиска).
+// Хранится в StateStore как JSON, поэтому все поля экспортируемые.
+type UserState struct {
+	Step   string  `json:"step"`
+	ChatID int64   `json:"chat_id"`
+	Event  Event   `json:"event"`
+	Events []Event `json:"events,omitempty"`
+	Index  int     `json:"index"`
+	// Field — редактируемое поле в мастере /edit_<id>
+	Field string `json:"field,omitempty"`
+}
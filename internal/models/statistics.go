@@ -1,7 +1,8 @@
 package models
 
 type Statistic struct {
-	ID    int64  `db:"id"`
-	Event string `db:"event"`
-	Data  string `db:"data"`
+	ID      int64  `db:"id"`
+	EventID string `db:"event_id"`
+	Event   string `db:"event"`
+	Data    string `db:"data"`
 }
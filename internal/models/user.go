@@ -2,9 +2,35 @@ package models
 
 import "time"
 
+// DefaultLocation — часовой пояс пользователей, которые не выбрали свой (задаётся из конфига при старте)
+var DefaultLocation = time.UTC
+
 type User struct {
 	ID        int64     `db:"id"`
 	Username  string    `db:"username"`
 	ChatID    int64     `db:"chat_id"`
+	Timezone  *string   `db:"timezone"`
 	CreatedAt time.Time `db:"created_at"` // для истории
 }
+
+// DisplayName — имя пользователя для сообщений: «@username» или «id<chat>»
+func (u User) DisplayName() string {
+	return DisplayName(u.Username, u.ChatID)
+}
+
+// Location — часовой пояс пользователя или DefaultLocation
+func (u User) Location() *time.Location {
+	return LoadLocation(u.Timezone)
+}
+
+// LoadLocation загружает часовой пояс по имени; пустое или неизвестное имя даёт DefaultLocation
+func LoadLocation(name *string) *time.Location {
+	if name == nil || *name == "" {
+		return DefaultLocation
+	}
+	loc, err := time.LoadLocation(*name)
+	if err != nil {
+		return DefaultLocation
+	}
+	return loc
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"fmt"
 	"github.com/jmoiron/sqlx"
 	"tg-bot/internal/models"
 )
@@ -13,25 +14,47 @@ func NewAuthPostgres(db *sqlx.DB) *AuthPostgres {
 	return &AuthPostgres{db: db}
 }
 
+// Create регистрирует пользователя (или обновляет username повторно зашедшего)
+// и в той же транзакции пишет в outbox событие user.created для новых пользователей
 func (r *AuthPostgres) Create(user models.User) (int64, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
 	var id int64
+	var inserted bool
+	// xmax = 0 только у только что вставленной строки, а не у обновлённой через ON CONFLICT
 	query := `
 		INSERT INTO users (username, chat_id)
 		VALUES ($1, $2)
 		ON CONFLICT (chat_id) DO UPDATE 
 		    SET username = EXCLUDED.username
-		RETURNING id;
+		RETURNING id, (xmax = 0) AS inserted;
 	`
-	err := r.db.QueryRow(query, user.Username, user.ChatID).Scan(&id)
+	err = tx.QueryRow(query, user.Username, user.ChatID).Scan(&id, &inserted)
 	if err != nil {
 		return 0, err
 	}
+	if inserted {
+		err = insertOutbox(tx, models.UserCreated{UserID: id, ChatID: user.ChatID, Username: user.Username}, user.ChatID)
+		if err != nil {
+			return 0, err
+		}
+	}
 	return id, nil
 }
 
 func (r *AuthPostgres) GetUserById(chatID int64) (models.User, error) {
 	var user models.User
-	query := `SELECT id, username, chat_id 
+	query := `SELECT id, COALESCE(username, '') AS username, chat_id, timezone 
 			  FROM users 
 			  WHERE chat_id = $1`
 	err := r.db.Get(&user, query, chatID)
@@ -41,3 +64,18 @@ func (r *AuthPostgres) GetUserById(chatID int64) (models.User, error) {
 
 	return user, nil
 }
+
+func (r *AuthPostgres) SetTimezone(chatID int64, timezone string) error {
+	result, err := r.db.Exec(`UPDATE users SET timezone = $2 WHERE chat_id = $1`, chatID, timezone)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with chat_id=%d not found", chatID)
+	}
+	return nil
+}
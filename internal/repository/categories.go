@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/jmoiron/sqlx"
+	"tg-bot/internal/models"
+)
+
+type CategoryPostgres struct {
+	db *sqlx.DB
+}
+
+func NewCategoryPostgres(db *sqlx.DB) *CategoryPostgres {
+	return &CategoryPostgres{db: db}
+}
+
+// GetAll возвращает каталог категорий с количеством опубликованных предстоящих событий
+func (r *CategoryPostgres) GetAll() ([]models.Category, error) {
+	var categories []models.Category
+	query := `
+		SELECT c.id, c.title, COUNT(e.id) AS event_count
+		FROM categories c
+		LEFT JOIN events e ON e.category_id = c.id AND e.status = 'published' AND e.date >= NOW()
+		GROUP BY c.id, c.title, c.sort_order
+		ORDER BY c.sort_order, c.title
+	`
+	err := r.db.Select(&categories, query)
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *CategoryPostgres) GetByID(id int64) (models.Category, error) {
+	var category models.Category
+	query := `SELECT id, title FROM categories WHERE id = $1`
+	err := r.db.Get(&category, query, id)
+	if err != nil {
+		return models.Category{}, err
+	}
+	return category, nil
+}
+
+// GetByTitle ищет категорию по названию без учёта регистра
+func (r *CategoryPostgres) GetByTitle(title string) (models.Category, error) {
+	var category models.Category
+	query := `SELECT id, title FROM categories WHERE LOWER(title) = LOWER(TRIM($1))`
+	err := r.db.Get(&category, query, title)
+	if err != nil {
+		return models.Category{}, err
+	}
+	return category, nil
+}
@@ -1,11 +1,25 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"slices"
+	"strings"
 	"tg-bot/internal/models"
+	"time"
 )
 
+// eventColumns — общий список колонок для выборки событий
+const eventColumns = "id, title, category, category_id, date, location, latitude, longitude, description, url, image_url, max_participants, join_policy, creator_id, creator_telegram_id, created_at, updated_at, status"
+
+// eventColumnsAs — eventColumns с псевдонимом таблицы alias для запросов с JOIN
+func eventColumnsAs(alias string) string {
+	return alias + "." + strings.ReplaceAll(eventColumns, ", ", ", "+alias+".")
+}
+
 type EventPostgres struct {
 	db *sqlx.DB
 }
@@ -38,32 +52,123 @@ func (r *EventPostgres) Create(event models.Event, chatID int64) (int64, error)
 	// 2️⃣ Создаём событие
 	var eventID int64
 	queryEvent := `
-		INSERT INTO events (title, category, date, location, description, url, image_url, creator_id, creator_chat_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'draft', NOW(), NOW())
+		INSERT INTO events (title, category, category_id, date, location, latitude, longitude, description, url, image_url, max_participants, 
+			join_policy, creator_id, creator_telegram_id, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
 		RETURNING id
 	`
 	err = tx.QueryRow(queryEvent,
 		event.Title,
 		event.Category,
+		event.CategoryID,
 		event.Date,
 		event.Location,
+		event.Latitude,
+		event.Longitude,
 		event.Description,
 		event.URL,
 		event.ImageURL,
+		event.MaxParticipants,
+		joinPolicy(event.JoinPolicy),
 		userID,
 		chatID,
+		models.EventDraft,
 	).Scan(&eventID)
 	if err != nil {
 		return 0, err
 	}
 
+	// 3️⃣ Доменное событие для статистики — в той же транзакции
+	created := models.EventCreated{
+		EventID:    eventID,
+		Title:      event.Title,
+		CategoryID: event.CategoryID,
+		JoinPolicy: joinPolicy(event.JoinPolicy),
+	}
+	err = insertOutbox(tx, created, chatID)
+	if err != nil {
+		return 0, err
+	}
+
 	return eventID, nil
 }
 
-func (r *EventPostgres) GetEvents() ([]models.Event, error) {
+// GetEvents возвращает страницу опубликованных предстоящих событий и их общее количество
+func (r *EventPostgres) GetEvents(limit, offset int) ([]models.Event, int, error) {
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE status = 'published' AND date >= NOW()`, events)
+	if err := r.db.Get(&total, countQuery); err != nil {
+		return nil, 0, err
+	}
+
+	var eventsList []models.Event
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE status = 'published' AND date >= NOW() ORDER BY date, id LIMIT $1 OFFSET $2`, eventColumns, events)
+	err := r.db.Select(&eventsList, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return eventsList, total, nil
+}
+
+// GetEventsInRange — страница опубликованных событий с датой в [from, to), ещё не начавшихся
+func (r *EventPostgres) GetEventsInRange(from, to time.Time, limit, offset int) ([]models.Event, int, error) {
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s 
+		WHERE status = 'published' AND date >= $1 AND date < $2 AND date >= NOW()`, events)
+	if err := r.db.Get(&total, countQuery, from, to); err != nil {
+		return nil, 0, err
+	}
+
 	var eventsList []models.Event
-	query := fmt.Sprintf(`SELECT id, title, category, date, location, description ,url, image_url, creator_id, created_at, updated_at, status FROM %s WHERE date >= NOW() ORDER BY date`, events)
-	err := r.db.Select(&eventsList, query)
+	query := fmt.Sprintf(`SELECT %s FROM %s 
+		WHERE status = 'published' AND date >= $1 AND date < $2 AND date >= NOW() 
+		ORDER BY date, id LIMIT $3 OFFSET $4`, eventColumns, events)
+	err := r.db.Select(&eventsList, query, from, to, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return eventsList, total, nil
+}
+
+// GetEventsByCategory — страница опубликованных предстоящих событий категории и их общее количество
+func (r *EventPostgres) GetEventsByCategory(categoryID int64, limit, offset int) ([]models.Event, int, error) {
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE category_id = $1 AND status = 'published' AND date >= NOW()`, events)
+	if err := r.db.Get(&total, countQuery, categoryID); err != nil {
+		return nil, 0, err
+	}
+
+	var eventsList []models.Event
+	query := fmt.Sprintf(`SELECT %s FROM %s 
+		WHERE category_id = $1 AND status = 'published' AND date >= NOW() 
+		ORDER BY date, id LIMIT $2 OFFSET $3`, eventColumns, events)
+	err := r.db.Select(&eventsList, query, categoryID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return eventsList, total, nil
+}
+
+// GetEventsNear возвращает опубликованные предстоящие события в радиусе radiusKm от точки,
+// отсортированные по расстоянию (формула гаверсинусов, радиус Земли 6371 км).
+// Предварительный фильтр по широте (1° ≈ 111 км) позволяет использовать индекс idx_events_geo.
+func (r *EventPostgres) GetEventsNear(lat, lon, radiusKm float64, limit int) ([]models.NearbyEvent, error) {
+	var eventsList []models.NearbyEvent
+	query := fmt.Sprintf(`SELECT * FROM (
+			SELECT %s, 
+				2 * 6371 * ASIN(LEAST(1, SQRT(
+					POWER(SIN(RADIANS(latitude - $1) / 2), 2) + 
+					COS(RADIANS($1)) * COS(RADIANS(latitude)) * POWER(SIN(RADIANS(longitude - $2) / 2), 2)
+				))) AS distance_km 
+			FROM %s 
+			WHERE status = 'published' AND date >= NOW() 
+				AND latitude IS NOT NULL AND longitude IS NOT NULL 
+				AND latitude BETWEEN $1 - $3 / 111.0 AND $1 + $3 / 111.0
+		) nearby 
+		WHERE distance_km <= $3 
+		ORDER BY distance_km, date 
+		LIMIT $4`, eventColumns, events)
+	err := r.db.Select(&eventsList, query, lat, lon, radiusKm, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -73,13 +178,13 @@ func (r *EventPostgres) GetEvents() ([]models.Event, error) {
 func (r *EventPostgres) GetMyEvents(chatID int64) ([]models.Event, error) {
 	var eventsList []models.Event
 
-	query := `
-		SELECT e.id, e.title, e.category, e.date, e.location, e.description, e.url, e.image_url, e.creator_id, e.created_at, e.updated_at, e.status
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM events e
 		JOIN users u ON e.creator_id = u.id
 		WHERE u.chat_id = $1
 		ORDER BY e.date
-	`
+	`, eventColumnsAs("e"))
 	err := r.db.Select(&eventsList, query, chatID)
 	if err != nil {
 		return nil, err
@@ -119,19 +224,28 @@ func (r *EventPostgres) DeleteEvent(eventID, chatID int64) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("event with id=%d not found or does not belong to user with chat_id=%d", eventID, chatID)
+		return fmt.Errorf("event with id=%d not found or does not belong to user with chat_id=%d: %w", eventID, chatID, ErrNotEventOwner)
 	}
 
 	return nil
 }
 
+// searchLimit ограничивает выдачу поиска: результаты листаются по одному и хранятся в состоянии диалога
+const searchLimit = 50
+
+// SearchEvents ищет по title, category, location и description через tsvector
+// и сортирует по релевантности (ts_rank), при равенстве — по дате
 func (r *EventPostgres) SearchEvents(query string) ([]models.Event, error) {
 	var eventsList []models.Event
-	searchQuery := fmt.Sprintf(`SELECT id, title, category, date, location, description ,url, image_url, creator_id, created_at, updated_at, status 
-		FROM %s 
-		WHERE (title ILIKE '%%' || $1 || '%%' OR description ILIKE '%%' || $1 || '%%') AND date >= NOW() 
-		ORDER BY date`, events)
-	err := r.db.Select(&eventsList, searchQuery, query)
+	searchQuery := fmt.Sprintf(`WITH q AS (
+			SELECT websearch_to_tsquery('russian', $1) || websearch_to_tsquery('simple', $1) AS query
+		)
+		SELECT %s 
+		FROM %s, q 
+		WHERE search_vector @@ q.query AND status = 'published' AND date >= NOW() 
+		ORDER BY ts_rank(search_vector, q.query) DESC, date 
+		LIMIT $2`, eventColumns, events)
+	err := r.db.Select(&eventsList, searchQuery, query, searchLimit)
 	if err != nil {
 		return nil, err
 	}
@@ -140,11 +254,11 @@ func (r *EventPostgres) SearchEvents(query string) ([]models.Event, error) {
 
 func (r *EventPostgres) SearchEventRandom() (models.Event, error) {
 	var event models.Event
-	query := fmt.Sprintf(`SELECT id, title, category, date, location, description ,url, image_url, creator_id, created_at, updated_at, status 
+	query := fmt.Sprintf(`SELECT %s 
 		FROM %s 
-		WHERE date >= NOW() 
+		WHERE status = 'published' AND date >= NOW() 
 		ORDER BY RANDOM() 
-		LIMIT 1`, events)
+		LIMIT 1`, eventColumns, events)
 	err := r.db.Get(&event, query)
 	if err != nil {
 		return models.Event{}, err
@@ -154,63 +268,648 @@ func (r *EventPostgres) SearchEventRandom() (models.Event, error) {
 
 func (r *EventPostgres) GetByID(id int64) (models.Event, error) {
 	var event models.Event
-	query := fmt.Sprintf(`SELECT id, title, category, date, location, description ,url, image_url, creator_id, created_at, updated_at, status 
+	query := fmt.Sprintf(`SELECT %s 
 		FROM %s 
-		WHERE id = $1`, events)
+		WHERE id = $1`, eventColumns, events)
 	err := r.db.Get(&event, query, id)
 	if err != nil {
 		return models.Event{}, err
 	}
 	return event, nil
 }
-func (r *EventPostgres) RequestJoin(eventID, chatID int64) error {
-	// Проверяем, существует ли событие
-	var exists bool
-	queryEvent := `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1 AND date >= NOW())`
-	err := r.db.Get(&exists, queryEvent, eventID)
-	if err != nil {
-		return err
+
+// joinPolicy — политика вступления по умолчанию для старых и не заполненных событий
+func joinPolicy(policy string) string {
+	if policy == "" {
+		return models.JoinManual
 	}
-	if !exists {
-		return fmt.Errorf("event with id=%d does not exist or has already occurred", eventID)
+	return policy
+}
+
+// RequestJoin сохраняет заявку с учётом политики события: при manual — pending,
+// при auto и invite (для приглашённых) — сразу approved или waitlisted, если мест нет.
+// Возвращает статус сохранённой заявки.
+func (r *EventPostgres) RequestJoin(eventID, chatID int64) (string, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", err
 	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
 
-	// Проверяем, существует ли пользователь по chat_id
-	var userID int64
-	queryUser := `SELECT id FROM users WHERE chat_id = $1`
-	err = r.db.Get(&userID, queryUser, chatID)
+	// 1️⃣ Проверяем, что событие опубликовано и ещё не прошло; блокируем его,
+	// чтобы параллельные автоодобрения не превысили лимит
+	var event struct {
+		CreatorID       int64  `db:"creator_id"`
+		MaxParticipants *int   `db:"max_participants"`
+		JoinPolicy      string `db:"join_policy"`
+	}
+	queryEvent := `SELECT creator_id, max_participants, join_policy FROM events 
+		WHERE id = $1 AND status = 'published' AND date >= NOW() FOR UPDATE`
+	err = tx.Get(&event, queryEvent, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("event with id=%d: %w", eventID, ErrEventUnavailable)
+		return "", err
+	}
 	if err != nil {
-		return fmt.Errorf("user with chat_id=%d not found: %w", chatID, err)
+		return "", err
 	}
 
-	// Проверяем, не является ли пользователь создателем события
-	var creatorID int64
-	queryCreator := `SELECT creator_id FROM events WHERE id = $1`
-	err = r.db.Get(&creatorID, queryCreator, eventID)
+	// 2️⃣ Находим пользователя по chat_id
+	var user struct {
+		ID       int64  `db:"id"`
+		Username string `db:"username"`
+	}
+	err = tx.Get(&user, `SELECT id, COALESCE(username, '') AS username FROM users WHERE chat_id = $1`, chatID)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("user with chat_id=%d not found: %w", chatID, err)
 	}
-	if creatorID == userID {
-		return fmt.Errorf("user with chat_id=%d is the creator of the event and cannot join it", chatID)
+	if event.CreatorID == user.ID {
+		err = fmt.Errorf("user with chat_id=%d: %w", chatID, ErrCreatorCannotJoin)
+		return "", err
 	}
 
-	// Проверяем, не отправлял ли пользователь уже заявку на это событие
+	// 3️⃣ Проверяем, не отправлял ли пользователь уже заявку на это событие
 	var requestExists bool
 	queryRequest := `SELECT EXISTS(SELECT 1 FROM event_participants WHERE event_id = $1 AND user_id = $2)`
-	err = r.db.Get(&requestExists, queryRequest, eventID, userID)
+	err = tx.Get(&requestExists, queryRequest, eventID, user.ID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if requestExists {
-		return fmt.Errorf("user with chat_id=%d has already requested to join event with id=%d", chatID, eventID)
+		err = fmt.Errorf("user with chat_id=%d, event id=%d: %w", chatID, eventID, ErrAlreadyRequested)
+		return "", err
+	}
+
+	// 4️⃣ Определяем статус заявки по политике события
+	status := models.ParticipantPending
+	switch event.JoinPolicy {
+	case models.JoinInvite:
+		var invited bool
+		queryInvite := `SELECT EXISTS(SELECT 1 FROM event_invites WHERE event_id = $1 AND username = LOWER($2))`
+		err = tx.Get(&invited, queryInvite, eventID, user.Username)
+		if err != nil {
+			return "", err
+		}
+		if !invited {
+			err = fmt.Errorf("user with chat_id=%d, event id=%d: %w", chatID, eventID, ErrNotInvited)
+			return "", err
+		}
+		status = models.ParticipantApproved
+	case models.JoinAuto:
+		status = models.ParticipantApproved
+	}
+	if status == models.ParticipantApproved && event.MaxParticipants != nil {
+		var approved int
+		err = tx.Get(&approved, `SELECT COUNT(*) FROM event_participants WHERE event_id = $1 AND status = 'approved'`, eventID)
+		if err != nil {
+			return "", err
+		}
+		if approved >= *event.MaxParticipants {
+			status = models.ParticipantWaitlisted
+		}
 	}
 
-	// Сохраняем заявку на участие
-	queryInsert := `INSERT INTO event_participants (event_id, user_id, requested_at) VALUES ($1, $2, NOW())`
-	_, err = r.db.Exec(queryInsert, eventID, userID)
+	// 5️⃣ Сохраняем заявку; confirmed_at получают только сразу одобренные — не лист ожидания
+	queryInsert := `INSERT INTO event_participants (event_id, user_id, status, requested_at, confirmed_at) 
+		VALUES ($1, $2, $3, NOW(), CASE WHEN $4 THEN NOW() END)`
+	_, err = tx.Exec(queryInsert, eventID, user.ID, status, status == models.ParticipantApproved)
 	if err != nil {
+		return "", err
+	}
+
+	// 6️⃣ Доменные события; автоодобренная заявка сразу даёт и participant.approved
+	err = insertOutbox(tx, models.JoinRequested{EventID: eventID, ChatID: chatID, Status: status}, chatID)
+	if err != nil {
+		return "", err
+	}
+	if status == models.ParticipantApproved {
+		err = insertOutbox(tx, models.JoinApproved{EventID: eventID, ChatID: chatID}, chatID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return status, nil
+}
+
+// AddInvites приглашает пользователей в событие creatorChatID по username.
+// Возвращает количество новых приглашений (повторные игнорируются).
+func (r *EventPostgres) AddInvites(eventID, creatorChatID int64, usernames []string) (int, error) {
+	var ownerChatID int64
+	err := r.db.Get(&ownerChatID, `SELECT creator_telegram_id FROM events WHERE id = $1`, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("event with id=%d: %w", eventID, ErrEventNotFound)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if ownerChatID != creatorChatID {
+		return 0, ErrNotEventOwner
+	}
+
+	query := `INSERT INTO event_invites (event_id, username) 
+		SELECT $1, LOWER(name) FROM UNNEST($2::text[]) AS name 
+		ON CONFLICT DO NOTHING`
+	result, err := r.db.Exec(query, eventID, pq.Array(usernames))
+	if err != nil {
+		return 0, err
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(added), nil
+}
+
+// ClaimJoinDigest отмечает автоматически одобренные заявки как попавшие в сводку и возвращает их.
+// Отметка ставится до отправки, чтобы параллельные запуски не продублировали сводку.
+func (r *EventPostgres) ClaimJoinDigest() ([]models.JoinDigestEntry, error) {
+	var entries []models.JoinDigestEntry
+	query := `
+		UPDATE event_participants ep
+		SET digested_at = NOW()
+		FROM events e, users u
+		WHERE ep.event_id = e.id
+		  AND ep.user_id = u.id
+		  AND ep.digested_at IS NULL
+		  AND e.join_policy <> 'manual'
+		  AND ep.status IN ('approved', 'waitlisted')
+		RETURNING ep.event_id, e.title AS event_title, e.creator_telegram_id AS creator_chat_id, 
+			u.chat_id, COALESCE(u.username, '') AS username, ep.status
+	`
+	err := r.db.Select(&entries, query)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Update сохраняет изменённые поля события, если оно принадлежит пользователю chatID
+func (r *EventPostgres) Update(event models.Event, chatID int64) error {
+	query := fmt.Sprintf(`UPDATE %s 
+		SET title = $3, category = $4, category_id = $5, date = $6, location = $7, latitude = $8, longitude = $9, 
+			description = $10, url = $11, image_url = $12, max_participants = $13, updated_at = NOW() 
+		WHERE id = $1 AND creator_telegram_id = $2`, events)
+	result, err := r.db.Exec(query,
+		event.ID,
+		chatID,
+		event.Title,
+		event.Category,
+		event.CategoryID,
+		event.Date,
+		event.Location,
+		event.Latitude,
+		event.Longitude,
+		event.Description,
+		event.URL,
+		event.ImageURL,
+		event.MaxParticipants,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("event with id=%d not found or does not belong to user with chat_id=%d: %w", event.ID, chatID, ErrNotEventOwner)
+	}
+	return nil
+}
+
+// UpdateStatus переводит событие владельца chatID в статус to, если текущий статус входит в from
+func (r *EventPostgres) UpdateStatus(eventID, chatID int64, from []string, to string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	// 1️⃣ Блокируем событие и проверяем владельца
+	var current struct {
+		OwnerChatID int64  `db:"creator_telegram_id"`
+		Status      string `db:"status"`
+	}
+	queryEvent := `SELECT creator_telegram_id, status FROM events WHERE id = $1 FOR UPDATE`
+	err = tx.Get(&current, queryEvent, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrEventNotFound
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if current.OwnerChatID != chatID {
+		err = ErrNotEventOwner
 		return err
 	}
 
+	// 2️⃣ Проверяем, что переход допустим
+	if !slices.Contains(from, current.Status) {
+		err = fmt.Errorf("event id=%d in status %q: %w", eventID, current.Status, ErrInvalidEventStatus)
+		return err
+	}
+
+	// 3️⃣ Меняем статус
+	queryUpdate := `UPDATE events SET status = $2, updated_at = NOW() WHERE id = $1`
+	_, err = tx.Exec(queryUpdate, eventID, to)
+	return err
+}
+
+// FinishExpiredEvents отмечает прошедшие события завершёнными (finished_at) и возвращает их.
+// Опубликованные события заодно закрываются; черновики остаются черновиками,
+// а закрытые создателем заранее тоже попадают в выборку — их участники ещё не поблагодарены.
+func (r *EventPostgres) FinishExpiredEvents() ([]models.Event, error) {
+	var eventsList []models.Event
+	query := fmt.Sprintf(`UPDATE %s e
+		SET finished_at = NOW(),
+		    status = CASE WHEN e.status = 'published' THEN 'closed' ELSE e.status END,
+		    updated_at = NOW()
+		WHERE e.finished_at IS NULL AND e.date < NOW()
+		RETURNING %s`, events, eventColumnsAs("e"))
+	err := r.db.Select(&eventsList, query)
+	if err != nil {
+		return nil, err
+	}
+	return eventsList, nil
+}
+
+// GetParticipants возвращает заявки на событие в указанных статусах (все, если статусы не заданы)
+func (r *EventPostgres) GetParticipants(eventID int64, statuses ...string) ([]models.Participant, error) {
+	var participants []models.Participant
+	query := `
+		SELECT ep.id, ep.event_id, ep.user_id, u.chat_id, COALESCE(u.username, '') AS username, u.timezone, ep.status, ep.requested_at, ep.confirmed_at
+		FROM event_participants ep
+		JOIN users u ON ep.user_id = u.id
+		WHERE ep.event_id = $1 AND (cardinality($2::text[]) = 0 OR ep.status = ANY($2))
+		ORDER BY ep.requested_at
+	`
+	err := r.db.Select(&participants, query, eventID, pq.Array(statuses))
+	if err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+// GetParticipantsPage — страница заявок события в статусах statuses:
+// сначала по порядку статусов в statuses, внутри статуса — в порядке подачи
+func (r *EventPostgres) GetParticipantsPage(eventID int64, statuses []string, limit, offset int) ([]models.Participant, error) {
+	var participants []models.Participant
+	query := `
+		SELECT ep.id, ep.event_id, ep.user_id, u.chat_id, COALESCE(u.username, '') AS username, u.timezone, ep.status, ep.requested_at, ep.confirmed_at
+		FROM event_participants ep
+		JOIN users u ON ep.user_id = u.id
+		WHERE ep.event_id = $1 AND ep.status = ANY($2)
+		ORDER BY array_position($2, ep.status::text), ep.requested_at, ep.id
+		LIMIT $3 OFFSET $4
+	`
+	err := r.db.Select(&participants, query, eventID, pq.Array(statuses), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return participants, nil
+}
+
+// CountParticipants — количество заявок события по статусам
+func (r *EventPostgres) CountParticipants(eventID int64) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	query := `SELECT status, COUNT(*) AS count FROM event_participants WHERE event_id = $1 GROUP BY status`
+	if err := r.db.Select(&rows, query, eventID); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ApproveAllPending одобряет все заявки pending события в порядке подачи по решению создателя.
+// Заявки сверх лимита участников уходят в лист ожидания. Возвращает обработанные заявки с новыми статусами.
+func (r *EventPostgres) ApproveAllPending(eventID, creatorChatID int64) ([]models.Participant, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	var event struct {
+		OwnerChatID     int64 `db:"creator_telegram_id"`
+		MaxParticipants *int  `db:"max_participants"`
+	}
+	err = tx.Get(&event, `SELECT creator_telegram_id, max_participants FROM events WHERE id = $1 FOR UPDATE`, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("event with id=%d: %w", eventID, ErrEventNotFound)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if event.OwnerChatID != creatorChatID {
+		err = ErrNotEventOwner
+		return nil, err
+	}
+
+	// Свободные места; NULL — без ограничения
+	var free *int
+	if event.MaxParticipants != nil {
+		var approved int
+		err = tx.Get(&approved, `SELECT COUNT(*) FROM event_participants WHERE event_id = $1 AND status = 'approved'`, eventID)
+		if err != nil {
+			return nil, err
+		}
+		n := max(*event.MaxParticipants-approved, 0)
+		free = &n
+	}
+
+	var decided []models.Participant
+	query := `
+		WITH pending AS (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY requested_at, id) AS n
+			FROM event_participants
+			WHERE event_id = $1 AND status = 'pending'
+		)
+		UPDATE event_participants ep
+		SET status = CASE WHEN $2::int IS NULL OR pending.n <= $2::int THEN 'approved' ELSE 'waitlisted' END,
+		    confirmed_at = CASE WHEN $2::int IS NULL OR pending.n <= $2::int THEN NOW() END
+		FROM pending, users u
+		WHERE ep.id = pending.id AND ep.user_id = u.id
+		RETURNING ep.id, ep.event_id, ep.user_id, u.chat_id, COALESCE(u.username, '') AS username, u.timezone, ep.status, ep.requested_at, ep.confirmed_at
+	`
+	err = tx.Select(&decided, query, eventID, free)
+	if err != nil {
+		return nil, err
+	}
+	err = insertApprovedOutbox(tx, decided, creatorChatID)
+	if err != nil {
+		return nil, err
+	}
+	return decided, nil
+}
+
+// ApproveRequest одобряет заявку. Если лимит участников уже исчерпан, заявка уходит
+// в лист ожидания. Возвращает итоговый статус: approved или waitlisted.
+func (r *EventPostgres) ApproveRequest(eventID, creatorChatID, participantChatID int64) (string, error) {
+	return r.decideRequest(eventID, creatorChatID, participantChatID, models.ParticipantApproved)
+}
+
+func (r *EventPostgres) RejectRequest(eventID, creatorChatID, participantChatID int64) error {
+	_, err := r.decideRequest(eventID, creatorChatID, participantChatID, models.ParticipantRejected)
+	return err
+}
+
+// decideRequest переводит заявку из pending (или waitlisted при отказе) в approved/waitlisted/rejected,
+// если решение принимает создатель события
+func (r *EventPostgres) decideRequest(eventID, creatorChatID, participantChatID int64, status string) (string, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	// 1️⃣ Проверяем, что решение принимает создатель события; блокируем событие,
+	// чтобы параллельные одобрения не превысили лимит
+	var event struct {
+		OwnerChatID     int64 `db:"creator_telegram_id"`
+		MaxParticipants *int  `db:"max_participants"`
+	}
+	queryOwner := `SELECT creator_telegram_id, max_participants FROM events WHERE id = $1 FOR UPDATE`
+	err = tx.Get(&event, queryOwner, eventID)
+	if err != nil {
+		return "", fmt.Errorf("event with id=%d not found: %w", eventID, err)
+	}
+	if event.OwnerChatID != creatorChatID {
+		err = ErrNotEventOwner
+		return "", err
+	}
+
+	// 2️⃣ Если мест нет, одобренная заявка встаёт в лист ожидания
+	from := []string{models.ParticipantPending}
+	if status == models.ParticipantRejected {
+		from = append(from, models.ParticipantWaitlisted)
+	}
+	if status == models.ParticipantApproved && event.MaxParticipants != nil {
+		var approved int
+		err = tx.Get(&approved, `SELECT COUNT(*) FROM event_participants WHERE event_id = $1 AND status = 'approved'`, eventID)
+		if err != nil {
+			return "", err
+		}
+		if approved >= *event.MaxParticipants {
+			status = models.ParticipantWaitlisted
+		}
+	}
+
+	// 3️⃣ Обновляем только необработанную заявку; confirmed_at ставится, только когда участник занял место
+	queryUpdate := `
+		UPDATE event_participants ep
+		SET status = $3,
+		    confirmed_at = CASE WHEN $5 THEN NOW() END
+		FROM users u
+		WHERE ep.user_id = u.id
+		  AND ep.event_id = $1
+		  AND u.chat_id = $2
+		  AND ep.status = ANY($4)
+	`
+	result, err := tx.Exec(queryUpdate, eventID, participantChatID, status, pq.Array(from), status == models.ParticipantApproved)
+	if err != nil {
+		return "", err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if rowsAffected == 0 {
+		err = ErrRequestNotFound
+		return "", err
+	}
+
+	if status == models.ParticipantApproved {
+		err = insertOutbox(tx, models.JoinApproved{EventID: eventID, ChatID: participantChatID}, creatorChatID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return status, nil
+}
+
+// GetUserRequests возвращает заявки пользователя chatID на предстоящие события, ближайшие первыми
+func (r *EventPostgres) GetUserRequests(chatID int64) ([]models.UserRequest, error) {
+	var requests []models.UserRequest
+	query := `
+		SELECT ep.event_id, e.title, e.date, e.location, ep.status, ep.requested_at, ep.confirmed_at
+		FROM event_participants ep
+		JOIN users u ON ep.user_id = u.id
+		JOIN events e ON ep.event_id = e.id
+		WHERE u.chat_id = $1 AND e.date >= NOW()
+		ORDER BY e.date, e.id
+	`
+	err := r.db.Select(&requests, query, chatID)
+	if err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// LeaveEvent удаляет заявку участника chatID (кроме отклонённой) и возвращает её прежний статус
+func (r *EventPostgres) LeaveEvent(eventID, chatID int64) (string, error) {
+	var status string
+	query := `
+		DELETE FROM event_participants ep
+		USING users u
+		WHERE ep.user_id = u.id
+		  AND ep.event_id = $1
+		  AND u.chat_id = $2
+		  AND ep.status <> 'rejected'
+		RETURNING ep.status
+	`
+	err := r.db.Get(&status, query, eventID, chatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("event id=%d, chat_id=%d: %w", eventID, chatID, ErrRequestNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// RemoveParticipant исключает одобренного участника или участника из листа ожидания
+// по решению создателя события; возвращает прежний статус участника
+func (r *EventPostgres) RemoveParticipant(eventID, creatorChatID, participantChatID int64) (string, error) {
+	var ownerChatID int64
+	err := r.db.Get(&ownerChatID, `SELECT creator_telegram_id FROM events WHERE id = $1`, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("event with id=%d: %w", eventID, ErrEventNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	if ownerChatID != creatorChatID {
+		return "", ErrNotEventOwner
+	}
+
+	var status string
+	query := `
+		DELETE FROM event_participants ep
+		USING users u
+		WHERE ep.user_id = u.id
+		  AND ep.event_id = $1
+		  AND u.chat_id = $2
+		  AND ep.status IN ('approved', 'waitlisted')
+		RETURNING ep.status
+	`
+	err = r.db.Get(&status, query, eventID, participantChatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("event id=%d, chat_id=%d: %w", eventID, participantChatID, ErrRequestNotFound)
+	}
+	if err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+// PromoteWaitlisted переводит участников из листа ожидания в одобренные, пока есть свободные места,
+// в порядке подачи заявок. Возвращает переведённых участников.
+func (r *EventPostgres) PromoteWaitlisted(eventID int64) ([]models.Participant, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		} else {
+			_ = tx.Commit()
+		}
+	}()
+
+	var maxParticipants *int
+	err = tx.Get(&maxParticipants, `SELECT max_participants FROM events WHERE id = $1 FOR UPDATE`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	var approved int
+	err = tx.Get(&approved, `SELECT COUNT(*) FROM event_participants WHERE event_id = $1 AND status = 'approved'`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	// Без лимита (его сняли при редактировании) переводим весь лист ожидания: LIMIT NULL
+	var free *int
+	if maxParticipants != nil {
+		n := *maxParticipants - approved
+		if n <= 0 {
+			return nil, nil
+		}
+		free = &n
+	}
+
+	var promoted []models.Participant
+	query := `
+		WITH next AS (
+			SELECT id FROM event_participants
+			WHERE event_id = $1 AND status = 'waitlisted'
+			ORDER BY requested_at, id
+			LIMIT $2
+		)
+		UPDATE event_participants ep
+		SET status = 'approved',
+		    confirmed_at = NOW()
+		FROM next, users u
+		WHERE ep.id = next.id AND ep.user_id = u.id
+		RETURNING ep.id, ep.event_id, ep.user_id, u.chat_id, COALESCE(u.username, '') AS username, u.timezone, ep.status, ep.requested_at, ep.confirmed_at
+	`
+	err = tx.Select(&promoted, query, eventID, free)
+	if err != nil {
+		return nil, err
+	}
+	// Перевод из листа ожидания делает система, а не пользователь: actor = 0
+	err = insertApprovedOutbox(tx, promoted, 0)
+	if err != nil {
+		return nil, err
+	}
+	return promoted, nil
+}
+
+// insertApprovedOutbox пишет participant.approved для каждого одобренного участника из списка
+func insertApprovedOutbox(tx *sqlx.Tx, participants []models.Participant, actorChatID int64) error {
+	for _, p := range participants {
+		if p.Status != models.ParticipantApproved {
+			continue
+		}
+		err := insertOutbox(tx, models.JoinApproved{EventID: p.EventID, ChatID: p.ChatID}, actorChatID)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
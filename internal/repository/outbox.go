@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"tg-bot/internal/models"
+)
+
+// insertOutbox упаковывает доменное событие в конверт и записывает его в outbox
+// в рамках транзакции изменения. actorChatID — кто совершил действие.
+func insertOutbox(tx *sqlx.Tx, event models.DomainEvent, actorChatID int64) error {
+	env, err := models.NewEnvelope(event, actorChatID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("marshal outbox %s: %w", env.Type, err)
+	}
+	_, err = tx.Exec(`INSERT INTO outbox (exchange, event_type, payload) VALUES ($1, $2, $3)`, models.EventsExchange, env.Type, body)
+	return err
+}
+
+type OutboxPostgres struct {
+	db *sqlx.DB
+}
+
+func NewOutboxPostgres(db *sqlx.DB) *OutboxPostgres {
+	return &OutboxPostgres{db: db}
+}
+
+// ClaimPending забирает до limit готовых к отправке сообщений и откладывает их на lease,
+// чтобы параллельный relay (другой экземпляр бота) не отправил их же.
+// Если отправка не завершится за lease, сообщения снова станут доступны.
+func (r *OutboxPostgres) ClaimPending(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+	query := `
+		UPDATE outbox o
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+		FROM (
+			SELECT id FROM outbox
+			WHERE sent_at IS NULL AND next_attempt_at <= NOW()
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) next
+		WHERE o.id = next.id
+		RETURNING o.id, o.exchange, o.event_type, o.payload, o.attempts, o.created_at
+	`
+	err := r.db.Select(&messages, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *OutboxPostgres) MarkSent(id int64) error {
+	_, err := r.db.Exec(`UPDATE outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = $1`, id)
+	return err
+}
+
+// MarkFailed фиксирует неудачную попытку и откладывает следующую на retryIn
+func (r *OutboxPostgres) MarkFailed(id int64, reason string, retryIn time.Duration) error {
+	query := `UPDATE outbox 
+		SET attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + $3 * INTERVAL '1 millisecond' 
+		WHERE id = $1`
+	_, err := r.db.Exec(query, id, reason, retryIn.Milliseconds())
+	return err
+}
+
+// DeleteSent удаляет отправленные сообщения старше olderThan
+func (r *OutboxPostgres) DeleteSent(olderThan time.Duration) error {
+	_, err := r.db.Exec(`DELETE FROM outbox WHERE sent_at < NOW() - $1 * INTERVAL '1 millisecond'`, olderThan.Milliseconds())
+	return err
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"tg-bot/internal/models"
+	"time"
+)
+
+type ReminderPostgres struct {
+	db *sqlx.DB
+}
+
+func NewReminderPostgres(db *sqlx.DB) *ReminderPostgres {
+	return &ReminderPostgres{db: db}
+}
+
+// GetDueReminders возвращает напоминания с отступом offset, ещё не отправленные участникам.
+// Событие попадает в окно, если до его начала осталось больше lower, но не больше offset.
+// Закрытый набор (closed) не отменяет напоминаний уже одобренным участникам.
+func (r *ReminderPostgres) GetDueReminders(offset, lower time.Duration) ([]models.Reminder, error) {
+	var reminders []models.Reminder
+	query := `
+		SELECT e.id AS event_id, u.id AS user_id, u.chat_id, u.timezone, e.title, e.date, COALESCE(e.location, '') AS location
+		FROM events e
+		JOIN event_participants ep ON ep.event_id = e.id
+		JOIN users u ON ep.user_id = u.id
+		WHERE e.status IN ('published', 'closed')
+		  AND ep.status = 'approved'
+		  AND ep.reminders_enabled
+		  AND e.date > NOW() + $2 * INTERVAL '1 minute'
+		  AND e.date <= NOW() + $1 * INTERVAL '1 minute'
+		  AND NOT EXISTS (
+		      SELECT 1 FROM event_reminders er
+		      WHERE er.event_id = e.id AND er.user_id = u.id AND er.offset_minutes = $1
+		  )
+		ORDER BY e.date
+	`
+	err := r.db.Select(&reminders, query, int(offset.Minutes()), int(lower.Minutes()))
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+// MarkSent фиксирует отправку напоминания. Возвращает false, если оно уже было отмечено
+// (например, другой репликой), — тогда отправлять повторно не нужно.
+func (r *ReminderPostgres) MarkSent(eventID, userID int64, offset time.Duration) (bool, error) {
+	query := `
+		INSERT INTO event_reminders (event_id, user_id, offset_minutes, sent_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (event_id, user_id, offset_minutes) DO NOTHING
+	`
+	result, err := r.db.Exec(query, eventID, userID, int(offset.Minutes()))
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// SetEnabled включает или отключает напоминания участника по конкретному событию
+func (r *ReminderPostgres) SetEnabled(eventID, chatID int64, enabled bool) error {
+	query := `
+		UPDATE event_participants ep
+		SET reminders_enabled = $3
+		FROM users u
+		WHERE ep.user_id = u.id AND ep.event_id = $1 AND u.chat_id = $2
+	`
+	result, err := r.db.Exec(query, eventID, chatID, enabled)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("event id=%d, chat_id=%d: %w", eventID, chatID, ErrRequestNotFound)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"errors"
 	"github.com/jmoiron/sqlx"
 	"tg-bot/internal/models"
+	"time"
 )
 
 const (
@@ -11,33 +13,97 @@ const (
 	stats  = "stats"
 )
 
+var (
+	ErrNotEventOwner   = errors.New("user is not the creator of the event")
+	ErrRequestNotFound = errors.New("participation request not found or already processed")
+
+	ErrEventUnavailable  = errors.New("event does not exist or has already occurred")
+	ErrCreatorCannotJoin = errors.New("creator of the event cannot join it")
+	ErrAlreadyRequested  = errors.New("user has already requested to join the event")
+	ErrNotInvited        = errors.New("event is invite-only and user is not invited")
+
+	ErrEventNotFound      = errors.New("event not found")
+	ErrInvalidEventStatus = errors.New("event status does not allow this action")
+)
+
 type Auth interface {
 	Create(user models.User) (int64, error)
 	GetUserById(chatID int64) (models.User, error)
+	SetTimezone(chatID int64, timezone string) error
 }
 type Stats interface {
 	Save(stat models.Statistic) error
 }
 type Events interface {
 	Create(event models.Event, chatID int64) (int64, error)
-	GetEvents() ([]models.Event, error)
+	GetEvents(limit, offset int) ([]models.Event, int, error)
+	GetEventsInRange(from, to time.Time, limit, offset int) ([]models.Event, int, error)
+	GetEventsByCategory(categoryID int64, limit, offset int) ([]models.Event, int, error)
+	GetEventsNear(lat, lon, radiusKm float64, limit int) ([]models.NearbyEvent, error)
 	GetMyEvents(chatID int64) ([]models.Event, error)
 	DeleteEvent(eventID, chatID int64) error
 	SearchEvents(query string) ([]models.Event, error)
 	SearchEventRandom() (models.Event, error)
 	GetByID(id int64) (models.Event, error)
-	RequestJoin(eventID, chatID int64) error
+	RequestJoin(eventID, chatID int64) (string, error)
+	AddInvites(eventID, creatorChatID int64, usernames []string) (int, error)
+	ClaimJoinDigest() ([]models.JoinDigestEntry, error)
+	Update(event models.Event, chatID int64) error
+	UpdateStatus(eventID, chatID int64, from []string, to string) error
+	FinishExpiredEvents() ([]models.Event, error)
+	GetParticipants(eventID int64, statuses ...string) ([]models.Participant, error)
+	GetParticipantsPage(eventID int64, statuses []string, limit, offset int) ([]models.Participant, error)
+	CountParticipants(eventID int64) (map[string]int, error)
+	ApproveAllPending(eventID, creatorChatID int64) ([]models.Participant, error)
+	ApproveRequest(eventID, creatorChatID, participantChatID int64) (string, error)
+	RejectRequest(eventID, creatorChatID, participantChatID int64) error
+	GetUserRequests(chatID int64) ([]models.UserRequest, error)
+	LeaveEvent(eventID, chatID int64) (string, error)
+	RemoveParticipant(eventID, creatorChatID, participantChatID int64) (string, error)
+	PromoteWaitlisted(eventID int64) ([]models.Participant, error)
+}
+type Categories interface {
+	GetAll() ([]models.Category, error)
+	GetByID(id int64) (models.Category, error)
+	GetByTitle(title string) (models.Category, error)
 }
+type Reminders interface {
+	GetDueReminders(offset, lower time.Duration) ([]models.Reminder, error)
+	MarkSent(eventID, userID int64, offset time.Duration) (bool, error)
+	SetEnabled(eventID, chatID int64, enabled bool) error
+}
+
+// StateStore хранит состояния диалогов пользователей с истечением по TTL.
+// Get возвращает nil без ошибки, если состояния нет или оно истекло.
+type StateStore interface {
+	Get(chatID int64) (*models.UserState, error)
+	Set(chatID int64, state *models.UserState) error
+	Delete(chatID int64) error
+	DeleteExpired() error
+}
+type Outbox interface {
+	ClaimPending(limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	MarkSent(id int64) error
+	MarkFailed(id int64, reason string, retryIn time.Duration) error
+	DeleteSent(olderThan time.Duration) error
+}
+
 type Repository struct {
 	Auth
 	Stats
 	Events
+	Categories
+	Reminders
+	Outbox
 }
 
 func NewRepository(db *sqlx.DB) *Repository {
 	return &Repository{
-		Auth:   NewAuthPostgres(db),
-		Stats:  NewStatsPostgres(db),
-		Events: NewEventPostgres(db),
+		Auth:       NewAuthPostgres(db),
+		Stats:      NewStatsPostgres(db),
+		Events:     NewEventPostgres(db),
+		Categories: NewCategoryPostgres(db),
+		Reminders:  NewReminderPostgres(db),
+		Outbox:     NewOutboxPostgres(db),
 	}
 }
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"tg-bot/internal/models"
+	"time"
+)
+
+// StatePostgres хранит состояния диалогов в user_states (JSONB), чтобы их не терять
+// при рестарте и разделять между репликами
+type StatePostgres struct {
+	db  *sqlx.DB
+	ttl time.Duration
+}
+
+func NewStatePostgres(db *sqlx.DB, ttl time.Duration) *StatePostgres {
+	return &StatePostgres{db: db, ttl: ttl}
+}
+
+func (r *StatePostgres) Get(chatID int64) (*models.UserState, error) {
+	var raw []byte
+	query := `SELECT state FROM user_states WHERE chat_id = $1 AND expires_at > NOW()`
+	err := r.db.Get(&raw, query, chatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state models.UserState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *StatePostgres) Set(chatID int64, state *models.UserState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO user_states (chat_id, state, expires_at, updated_at)
+		VALUES ($1, $2, NOW() + $3 * INTERVAL '1 second', NOW())
+		ON CONFLICT (chat_id) DO UPDATE
+		    SET state = EXCLUDED.state,
+		        expires_at = EXCLUDED.expires_at,
+		        updated_at = EXCLUDED.updated_at
+	`
+	_, err = r.db.Exec(query, chatID, raw, int64(r.ttl.Seconds()))
+	return err
+}
+
+func (r *StatePostgres) Delete(chatID int64) error {
+	_, err := r.db.Exec(`DELETE FROM user_states WHERE chat_id = $1`, chatID)
+	return err
+}
+
+// DeleteExpired удаляет просроченные состояния; вызывается по расписанию
+func (r *StatePostgres) DeleteExpired() error {
+	_, err := r.db.Exec(`DELETE FROM user_states WHERE expires_at <= NOW()`)
+	return err
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"sync"
+	"tg-bot/internal/models"
+	"time"
+)
+
+type memoryState struct {
+	state     models.UserState
+	expiresAt time.Time
+}
+
+// StateMemory — in-memory реализация StateStore для одной реплики и локальной разработки
+type StateMemory struct {
+	mu     sync.RWMutex
+	states map[int64]memoryState
+	ttl    time.Duration
+}
+
+func NewStateMemory(ttl time.Duration) *StateMemory {
+	return &StateMemory{states: make(map[int64]memoryState), ttl: ttl}
+}
+
+func (r *StateMemory) Get(chatID int64) (*models.UserState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.states[chatID]
+	if !ok || time.Now().After(s.expiresAt) {
+		return nil, nil
+	}
+	// Возвращаем копию, чтобы изменения сохранялись только через Set
+	state := s.state
+	return &state, nil
+}
+
+func (r *StateMemory) Set(chatID int64, state *models.UserState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[chatID] = memoryState{state: *state, expiresAt: time.Now().Add(r.ttl)}
+	return nil
+}
+
+func (r *StateMemory) Delete(chatID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.states, chatID)
+	return nil
+}
+
+func (r *StateMemory) DeleteExpired() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for chatID, s := range r.states {
+		if now.After(s.expiresAt) {
+			delete(r.states, chatID)
+		}
+	}
+	return nil
+}
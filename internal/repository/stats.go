@@ -13,7 +13,10 @@ func NewStatsPostgres(db *sqlx.DB) *StatsPostgres {
 	return &StatsPostgres{db: db}
 }
 
+// Save сохраняет запись статистики; повтор с тем же EventID игнорируется
 func (r *StatsPostgres) Save(stat models.Statistic) error {
-	_, err := r.db.Exec(`INSERT INTO statistics (event, data) VALUES ($1, $2)`, stat.Event, stat.Data)
+	query := `INSERT INTO statistics (event_id, event, data) VALUES (NULLIF($1, ''), $2, $3) 
+		ON CONFLICT (event_id) DO NOTHING`
+	_, err := r.db.Exec(query, stat.EventID, stat.Event, stat.Data)
 	return err
 }
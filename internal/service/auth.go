@@ -1,39 +1,45 @@
 package service
 
 import (
-	"encoding/json"
-	"tg-bot/internal/adapters/rabbitmq"
+	"fmt"
 	"tg-bot/internal/models"
 	"tg-bot/internal/repository"
+	"time"
 )
 
 type AuthService struct {
-	repo   repository.Auth
-	broker *rabbitmq.RabbitMQ
+	repo repository.Auth
 }
 
-func NewAuthService(repo repository.Auth, rmq *rabbitmq.RabbitMQ) *AuthService {
-	return &AuthService{repo: repo, broker: rmq}
+func NewAuthService(repo repository.Auth) *AuthService {
+	return &AuthService{repo: repo}
 }
-func (s *AuthService) Create(user models.User) (int64, error) {
-	createdUser, err := s.repo.Create(user)
-	if err != nil {
-		return 0, err
-	}
-
-	_, _ = s.broker.DeclareQueue("user.events")
 
-	event := map[string]interface{}{
-		"event": "user_created",
-		"user":  createdUser,
-	}
-	body, _ := json.Marshal(event)
-
-	// Публикуем в очередь user.events
-	_ = s.broker.Publish("user.events", body)
-
-	return createdUser, nil
+// Create регистрирует пользователя; событие user.created (models.DomainUserCreated) уходит в брокер через outbox
+func (s *AuthService) Create(user models.User) (int64, error) {
+	return s.repo.Create(user)
 }
 func (s *AuthService) GetUserById(id int64) (models.User, error) {
 	return s.repo.GetUserById(id)
 }
+
+// SetTimezone сохраняет часовой пояс пользователя (IANA-имя, например Asia/Almaty)
+func (s *AuthService) SetTimezone(chatID int64, timezone string) (*time.Location, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "" || timezone == "Local" {
+		return nil, fmt.Errorf("%q: %w", timezone, ErrInvalidTimezone)
+	}
+	if err := s.repo.SetTimezone(chatID, loc.String()); err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// GetLocation возвращает часовой пояс пользователя; для незарегистрированных — пояс по умолчанию
+func (s *AuthService) GetLocation(chatID int64) *time.Location {
+	user, err := s.repo.GetUserById(chatID)
+	if err != nil {
+		return models.DefaultLocation
+	}
+	return user.Location()
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+)
+
+type CategoryService struct {
+	repo repository.Categories
+}
+
+func NewCategoryService(repo repository.Categories) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+func (s *CategoryService) GetCategories() ([]models.Category, error) {
+	categories, err := s.repo.GetAll()
+	if err != nil {
+		logrus.Infof("Error getting categories: %s", err)
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (s *CategoryService) GetCategory(id int64) (models.Category, error) {
+	return s.repo.GetByID(id)
+}
+
+// FindCategory ищет категорию по названию, введённому вручную
+func (s *CategoryService) FindCategory(title string) (models.Category, error) {
+	return s.repo.GetByTitle(title)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/adapters/rabbitmq"
+	"tg-bot/internal/models"
+)
+
+const (
+	// deadLetterExchange — обменник, через который сообщения попадают в DLQ своей очереди
+	deadLetterExchange = "dlx"
+
+	headerRetryCount = "x-retry-count"
+	headerLastError  = "x-last-error"
+	headerFailedAt   = "x-failed-at"
+
+	// maxErrorLength — ограничение длины текста ошибки в заголовке сообщения
+	maxErrorLength = 500
+)
+
+// RetryPolicy — сколько раз и с какой паузой потребитель повторяет обработку сообщения
+type RetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+// DeadLetterService раскладывает необработанные сообщения по очередям повтора и DLQ.
+// Для каждой очереди потребителя q объявляются:
+//   - q.retry — без потребителей; сообщение лежит там Delay и по TTL возвращается в q;
+//   - q.dlq — привязана к обменнику dlx с ключом q; сюда попадают сообщения после MaxAttempts
+//     попыток и сразу — не прошедшие проверку схемы.
+type DeadLetterService struct {
+	broker *rabbitmq.RabbitMQ
+	policy RetryPolicy
+
+	mu     sync.Mutex
+	queues []string // очереди, для которых вызван Setup
+}
+
+func NewDeadLetterService(rmq *rabbitmq.RabbitMQ, policy RetryPolicy) *DeadLetterService {
+	return &DeadLetterService{broker: rmq, policy: policy}
+}
+
+func retryQueue(queue string) string      { return queue + ".retry" }
+func deadLetterQueue(queue string) string { return queue + ".dlq" }
+
+// Setup объявляет очередь повтора и DLQ для очереди потребителя queue
+func (s *DeadLetterService) Setup(queue string) error {
+	if err := s.broker.DeclareExchange(deadLetterExchange, amqp.ExchangeDirect); err != nil {
+		return err
+	}
+	if _, err := s.broker.DeclareQueue(deadLetterQueue(queue)); err != nil {
+		return err
+	}
+	if err := s.broker.BindQueue(deadLetterQueue(queue), queue, deadLetterExchange); err != nil {
+		return err
+	}
+	// По истечении TTL сообщение «умирает» и через обменник по умолчанию возвращается в queue
+	_, err := s.broker.DeclareQueueArgs(retryQueue(queue), amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queue,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.queues = append(s.queues, queue)
+	s.mu.Unlock()
+	return nil
+}
+
+// Retry перекладывает сообщение, обработка которого завершилась ошибкой cause:
+// в очередь повтора с отложенной доставкой или, если попытки исчерпаны, в DLQ.
+// После успешного вызова исходное сообщение нужно подтвердить (Ack).
+func (s *DeadLetterService) Retry(queue string, msg amqp.Delivery, cause error) error {
+	attempts := retryCount(msg.Headers) + 1
+	headers := userHeaders(msg.Headers)
+	headers[headerRetryCount] = int32(attempts)
+	headers[headerLastError] = truncate(cause.Error(), maxErrorLength)
+	headers[headerFailedAt] = time.Now().UTC()
+	pub := amqp.Publishing{
+		ContentType: msg.ContentType,
+		MessageId:   msg.MessageId,
+		Headers:     headers,
+		Body:        msg.Body,
+	}
+
+	if errors.Is(cause, models.ErrInvalidDomainEvent) || attempts >= s.policy.MaxAttempts {
+		logrus.Errorf("consumer: %s: message dead-lettered after %d attempt(s): %s", queue, attempts, cause)
+		return s.broker.PublishMessage(deadLetterExchange, queue, pub)
+	}
+	logrus.Warnf("consumer: %s: attempt %d failed, retry in %s: %s", queue, attempts, s.policy.Delay, cause)
+	pub.Expiration = strconv.FormatInt(s.policy.Delay.Milliseconds(), 10)
+	return s.broker.PublishMessage("", retryQueue(queue), pub)
+}
+
+// List возвращает до limit сообщений из DLQ всех очередей, не удаляя их, и общее число сообщений в DLQ
+func (s *DeadLetterService) List(limit int) ([]models.DeadLetter, int, error) {
+	var (
+		letters []models.DeadLetter
+		total   int
+	)
+	for _, queue := range s.registered() {
+		n, err := s.broker.QueueLength(deadLetterQueue(queue))
+		if err != nil {
+			logrus.Errorf("Error reading DLQ length of %s: %s", queue, err)
+			return nil, 0, err
+		}
+		total += n
+		if n == 0 || len(letters) >= limit {
+			continue
+		}
+
+		messages, err := s.broker.Peek(deadLetterQueue(queue), limit-len(letters))
+		if err != nil {
+			logrus.Errorf("Error reading DLQ of %s: %s", queue, err)
+			return nil, 0, err
+		}
+		for _, msg := range messages {
+			letters = append(letters, toDeadLetter(queue, msg))
+		}
+	}
+	return letters, total, nil
+}
+
+// Replay возвращает до limit сообщений из DLQ в исходные очереди со сброшенным счётчиком попыток.
+// Возвращает количество переотправленных сообщений.
+func (s *DeadLetterService) Replay(limit int) (int, error) {
+	replayed := 0
+	for _, queue := range s.registered() {
+		if replayed >= limit {
+			break
+		}
+		n, err := s.broker.Take(deadLetterQueue(queue), limit-replayed, func(msg amqp.Delivery) error {
+			return s.broker.PublishMessage("", queue, amqp.Publishing{
+				ContentType: msg.ContentType,
+				MessageId:   msg.MessageId,
+				Headers:     userHeaders(msg.Headers),
+				Body:        msg.Body,
+			})
+		})
+		replayed += n
+		if err != nil {
+			logrus.Errorf("Error replaying DLQ of %s: %s", queue, err)
+			return replayed, err
+		}
+	}
+	if replayed > 0 {
+		logrus.Infof("dlq: replayed %d message(s)", replayed)
+	}
+	return replayed, nil
+}
+
+func (s *DeadLetterService) registered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.queues...)
+}
+
+// toDeadLetter достаёт из сообщения DLQ служебные заголовки и тип события
+func toDeadLetter(queue string, msg amqp.Delivery) models.DeadLetter {
+	letter := models.DeadLetter{
+		Queue:    queue,
+		Attempts: retryCount(msg.Headers),
+		Body:     msg.Body,
+	}
+	letter.LastError, _ = msg.Headers[headerLastError].(string)
+	letter.FailedAt, _ = msg.Headers[headerFailedAt].(time.Time)
+
+	// Конверт может быть битым — поэтому тип читается без проверки схемы
+	var env struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(msg.Body, &env) == nil {
+		letter.Type = env.Type
+	}
+	return letter
+}
+
+// retryCount — число уже неудавшихся попыток обработки из заголовка x-retry-count
+func retryCount(headers amqp.Table) int {
+	switch v := headers[headerRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// userHeaders копирует заголовки без служебных "x-…": брокер сам добавляет x-death при
+// истечении TTL, а счётчик попыток выставляется заново
+func userHeaders(headers amqp.Table) amqp.Table {
+	out := amqp.Table{}
+	for k, v := range headers {
+		if !strings.HasPrefix(k, "x-") {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
@@ -3,38 +3,91 @@ package service
 import (
 	"context"
 	"fmt"
-	"github.com/mymmrac/telego"
 	"github.com/sirupsen/logrus"
+	"regexp"
+	"strings"
 	"tg-bot/internal/adapters/rabbitmq"
+	"tg-bot/internal/app"
 	"tg-bot/internal/models"
 	"tg-bot/internal/repository"
+	"time"
 )
 
 type EventService struct {
-	repo    repository.Events
-	repAuth repository.Auth
-	bot     *telego.Bot
-	broker  *rabbitmq.RabbitMQ
+	repo     repository.Events
+	repAuth  repository.Auth
+	notifier app.Notifier
+	broker   *rabbitmq.RabbitMQ
 }
 
-func NewEventService(repo repository.Events, repAuth repository.Auth, rmq *rabbitmq.RabbitMQ) *EventService {
-	return &EventService{repo: repo, repAuth: repAuth, broker: rmq}
+func NewEventService(repo repository.Events, repAuth repository.Auth, rmq *rabbitmq.RabbitMQ, notifier app.Notifier) *EventService {
+	return &EventService{repo: repo, repAuth: repAuth, broker: rmq, notifier: notifier}
 }
 
 func (s *EventService) Create(event models.Event, chatID int64) (int64, error) {
+	if err := validateEvent(event); err != nil {
+		return 0, err
+	}
 	id, err := s.repo.Create(event, chatID)
 	if err != nil {
 		return 0, err
 	}
 	return id, nil
 }
-func (s *EventService) GetEvents() ([]models.Event, error) {
-	events, err := s.repo.GetEvents()
+
+// EventsPageSize — сколько событий показывается на одной странице ленты
+const EventsPageSize = 5
+
+func (s *EventService) GetEvents(page int) (models.EventPage, error) {
+	if page < 0 {
+		page = 0
+	}
+	events, total, err := s.repo.GetEvents(EventsPageSize, page*EventsPageSize)
 	if err != nil {
 		logrus.Infof("Error getting events: %s", err)
-		return nil, err
+		return models.EventPage{}, err
 	}
 
+	return models.EventPage{Events: events, Page: page, PageSize: EventsPageSize, Total: total}, nil
+}
+
+func (s *EventService) GetEventsInRange(from, to time.Time, page int) (models.EventPage, error) {
+	if page < 0 {
+		page = 0
+	}
+	events, total, err := s.repo.GetEventsInRange(from, to, EventsPageSize, page*EventsPageSize)
+	if err != nil {
+		logrus.Infof("Error getting events in range: %s", err)
+		return models.EventPage{}, err
+	}
+	return models.EventPage{Events: events, Page: page, PageSize: EventsPageSize, Total: total}, nil
+}
+
+func (s *EventService) GetEventsByCategory(categoryID int64, page int) (models.EventPage, error) {
+	if page < 0 {
+		page = 0
+	}
+	events, total, err := s.repo.GetEventsByCategory(categoryID, EventsPageSize, page*EventsPageSize)
+	if err != nil {
+		logrus.Infof("Error getting events by category: %s", err)
+		return models.EventPage{}, err
+	}
+	return models.EventPage{Events: events, Page: page, PageSize: EventsPageSize, Total: total}, nil
+}
+
+// NearbyLimit — сколько ближайших событий показывать в ответ на «рядом со мной»
+const NearbyLimit = 10
+
+// GetEventsNear — ближайшие опубликованные события в радиусе radiusKm, от ближних к дальним
+func (s *EventService) GetEventsNear(lat, lon, radiusKm float64) ([]models.NearbyEvent, error) {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 || radiusKm <= 0 {
+		return nil, fmt.Errorf("invalid point %.5f,%.5f or radius %.1f: %w", lat, lon, radiusKm, ErrInvalidEvent)
+	}
+	events, err := s.repo.GetEventsNear(lat, lon, radiusKm, NearbyLimit)
+	if err != nil {
+		logrus.Infof("Error getting events near: %s", err)
+		return nil, err
+	}
 	return events, nil
 }
 
@@ -47,12 +100,193 @@ func (s *EventService) GetMyEvents(chatID int64) ([]models.Event, error) {
 	return events, err
 }
 
+// DeleteEvent удаляет событие владельца. Список участников берём до удаления,
+// т.к. каскад очищает event_participants, и после успешного удаления сообщаем им об отмене.
 func (s *EventService) DeleteEvent(eventID, chatID int64) error {
-	err := s.repo.DeleteEvent(eventID, chatID)
+	event, err := s.repo.GetByID(eventID)
+	if err != nil {
+		logrus.Infof("Error getting event: %s", err)
+		return err
+	}
+	participants, err := s.repo.GetParticipants(eventID, models.ParticipantPending, models.ParticipantApproved, models.ParticipantWaitlisted)
+	if err != nil {
+		logrus.Infof("Error getting participants: %s", err)
+		return err
+	}
+
+	err = s.repo.DeleteEvent(eventID, chatID)
 	if err != nil {
 		logrus.Infof("Error deleting event: %s", err)
 		return err
 	}
+
+	for _, p := range participants {
+		data := map[string]any{"Event": event, "Date": event.Date.In(models.LoadLocation(p.Timezone))}
+		if err := s.notifier.SendTemplate(context.Background(), p.ChatID, app.TemplateEventCancelled, data, nil); err != nil {
+			logrus.Errorf("Error notifying participant %d: %s", p.ChatID, err)
+		}
+	}
+	return nil
+}
+
+// UpdateEvent сохраняет одно отредактированное поле field из edited. Остальные поля берутся
+// из текущей версии события, чтобы не откатить изменения, сделанные, пока пользователь вводил
+// новое значение. Если изменились дата или место, одобренные участники получают уведомление.
+// Возвращает сохранённое событие.
+func (s *EventService) UpdateEvent(edited models.Event, field string, chatID int64) (models.Event, error) {
+	old, err := s.repo.GetByID(edited.ID)
+	if err != nil {
+		logrus.Infof("Error getting event: %s", err)
+		return models.Event{}, err
+	}
+	if old.CreatorTgID != chatID {
+		return models.Event{}, repository.ErrNotEventOwner
+	}
+	event := old
+	if err := copyEventField(&event, edited, field); err != nil {
+		return models.Event{}, err
+	}
+	if err := validateEvent(event); err != nil {
+		return models.Event{}, err
+	}
+	if err := s.repo.Update(event, chatID); err != nil {
+		logrus.Infof("Error updating event: %s", err)
+		return models.Event{}, err
+	}
+	if capacityGrew(old.MaxParticipants, event.MaxParticipants) {
+		s.promoteWaitlisted(event.ID)
+	}
+
+	if old.Date.Equal(event.Date) && old.Location == event.Location && sameGeo(old, event) {
+		return event, nil
+	}
+	participants, err := s.repo.GetParticipants(event.ID, models.ParticipantApproved)
+	if err != nil {
+		logrus.Errorf("Error getting participants of event %d: %s", event.ID, err)
+		return event, nil
+	}
+	for _, p := range participants {
+		data := map[string]any{"Event": event, "Date": event.Date.In(models.LoadLocation(p.Timezone))}
+		if err := s.notifier.SendTemplate(context.Background(), p.ChatID, app.TemplateEventChanged, data, nil); err != nil {
+			logrus.Errorf("Error notifying participant %d: %s", p.ChatID, err)
+		}
+	}
+	return event, nil
+}
+
+// copyEventField переносит в event колонки поля мастера редактирования field из edited
+func copyEventField(event *models.Event, edited models.Event, field string) error {
+	switch field {
+	case "title":
+		event.Title = edited.Title
+	case "category":
+		event.Category, event.CategoryID = edited.Category, edited.CategoryID
+	case "description":
+		event.Description = edited.Description
+	case "date":
+		event.Date = edited.Date
+	case "location":
+		event.Location, event.Latitude, event.Longitude = edited.Location, edited.Latitude, edited.Longitude
+	case "url":
+		event.URL = edited.URL
+	case "capacity":
+		event.MaxParticipants = edited.MaxParticipants
+	case "image":
+		event.ImageURL = edited.ImageURL
+	default:
+		return fmt.Errorf("unknown field %q: %w", field, ErrInvalidEvent)
+	}
+	return nil
+}
+
+// sameGeo — совпадают ли координаты места у двух версий события
+func sameGeo(a, b models.Event) bool {
+	if a.HasGeo() != b.HasGeo() {
+		return false
+	}
+	return !a.HasGeo() || (*a.Latitude == *b.Latitude && *a.Longitude == *b.Longitude)
+}
+
+// capacityGrew — стало ли мест больше (nil — без ограничения)
+func capacityGrew(old, updated *int) bool {
+	if updated == nil {
+		return old != nil
+	}
+	return old != nil && *updated > *old
+}
+
+// validateEvent проверяет обязательные поля события
+func validateEvent(event models.Event) error {
+	if strings.TrimSpace(event.Title) == "" {
+		return fmt.Errorf("title is empty: %w", ErrInvalidEvent)
+	}
+	if event.Date.IsZero() {
+		return fmt.Errorf("date is empty: %w", ErrInvalidEvent)
+	}
+	if event.MaxParticipants != nil && *event.MaxParticipants <= 0 {
+		return fmt.Errorf("max participants must be positive: %w", ErrInvalidEvent)
+	}
+	switch event.JoinPolicy {
+	case "", models.JoinManual, models.JoinAuto, models.JoinInvite:
+	default:
+		return fmt.Errorf("unknown join policy %q: %w", event.JoinPolicy, ErrInvalidEvent)
+	}
+	return nil
+}
+
+// PublishEvent делает черновик видимым в общих списках
+func (s *EventService) PublishEvent(eventID, chatID int64) error {
+	err := s.repo.UpdateStatus(eventID, chatID, []string{models.EventDraft}, models.EventPublished)
+	if err != nil {
+		logrus.Infof("Error publishing event: %s", err)
+		return err
+	}
+	return nil
+}
+
+// CloseEvent закрывает событие: оно пропадает из общих списков и не принимает заявки
+func (s *EventService) CloseEvent(eventID, chatID int64) error {
+	err := s.repo.UpdateStatus(eventID, chatID, []string{models.EventDraft, models.EventPublished}, models.EventClosed)
+	if err != nil {
+		logrus.Infof("Error closing event: %s", err)
+		return err
+	}
+	return nil
+}
+
+// CheckAndUpdateEvents завершает прошедшие события, благодарит одобренных участников
+// и отправляет создателю итог по количеству участников. Неопубликованные черновики
+// завершаются молча.
+func (s *EventService) CheckAndUpdateEvents() error {
+	finished, err := s.repo.FinishExpiredEvents()
+	if err != nil {
+		logrus.Infof("Error finishing expired events: %s", err)
+		return err
+	}
+
+	ctx := context.Background()
+	for _, event := range finished {
+		if event.Status == models.EventDraft {
+			continue
+		}
+		participants, err := s.repo.GetParticipants(event.ID, models.ParticipantApproved)
+		if err != nil {
+			logrus.Errorf("Error getting participants of event %d: %s", event.ID, err)
+			continue
+		}
+		for _, p := range participants {
+			data := map[string]any{"Event": event}
+			if err := s.notifier.SendTemplate(ctx, p.ChatID, app.TemplateEventThanks, data, nil); err != nil {
+				logrus.Errorf("Error notifying participant %d: %s", p.ChatID, err)
+			}
+		}
+		data := map[string]any{"Event": event, "Count": len(participants)}
+		if err := s.notifier.SendTemplate(ctx, event.CreatorTgID, app.TemplateEventSummary, data, nil); err != nil {
+			logrus.Errorf("Error notifying creator of event %d: %s", event.ID, err)
+		}
+	}
+
+	logrus.Infof("Finished %d expired events", len(finished))
 	return nil
 }
 
@@ -73,63 +307,298 @@ func (s *EventService) SearchEventRandom() (models.Event, error) {
 	}
 	return event, nil
 }
-func (s *EventService) RequestJoin(eventID, chatID int64) error {
+
+// RequestJoin подаёт заявку по политике события и возвращает её статус.
+// Заявки на события с ручным подтверждением сразу уходят создателю с кнопками решения,
+// автоматически одобренные — попадают в периодическую сводку (SendJoinDigests).
+func (s *EventService) RequestJoin(eventID, chatID int64) (string, error) {
 	// Проверяем и сохраняем заявку
-	if err := s.repo.RequestJoin(eventID, chatID); err != nil {
-		return err
+	status, err := s.repo.RequestJoin(eventID, chatID)
+	if err != nil {
+		return "", err
+	}
+	switch status {
+	case models.ParticipantApproved:
+		s.notifyParticipant(eventID, chatID, app.TemplateRequestApproved)
+		return status, nil
+	case models.ParticipantWaitlisted:
+		s.notifyParticipant(eventID, chatID, app.TemplateRequestWaitlisted)
+		return status, nil
 	}
 
 	// Получаем данные события
 	event, err := s.repo.GetByID(eventID)
 	if err != nil {
-		return fmt.Errorf("failed to get event: %w", err)
+		return "", fmt.Errorf("failed to get event: %w", err)
 	}
 
 	// Получаем данные участника
 	user, err := s.repAuth.GetUserById(chatID)
 	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
+		return "", fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Получаем создателя события (используем CreatorChatID)
-	creator, err := s.repAuth.GetUserById(event.CreatorTgID)
+	buttons := [][]app.Button{
+		{
+			{Text: "✅ Принять", CallbackData: fmt.Sprintf("approve_%d_%d", eventID, chatID)},
+			{Text: "❌ Отклонить", CallbackData: fmt.Sprintf("reject_%d_%d", eventID, chatID)},
+		},
+	}
+
+	// Отправляем владельцу события уведомление
+	data := map[string]any{"Event": event, "User": user}
+	// Заявка уже сохранена, поэтому ошибку отправки только логируем
+	if err := s.notifier.SendTemplate(context.Background(), event.CreatorTgID, app.TemplateJoinRequest, data, buttons); err != nil {
+		logrus.Errorf("failed to notify event creator: %s", err)
+	}
+
+	return status, nil
+}
+
+// usernameRe — допустимый username Telegram
+var usernameRe = regexp.MustCompile(`^[A-Za-z0-9_]{5,32}$`)
+
+// AddInvites приглашает пользователей по username (с @ или без) в событие с политикой invite.
+// Возвращает количество новых приглашений; некорректные имена дают ErrInvalidUsername.
+func (s *EventService) AddInvites(eventID, creatorChatID int64, usernames []string) (int, error) {
+	var names []string
+	for _, name := range usernames {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+		if name == "" {
+			continue
+		}
+		if !usernameRe.MatchString(name) {
+			return 0, fmt.Errorf("username %q: %w", name, ErrInvalidUsername)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return 0, fmt.Errorf("no usernames: %w", ErrInvalidUsername)
+	}
+	added, err := s.repo.AddInvites(eventID, creatorChatID, names)
 	if err != nil {
-		return fmt.Errorf("failed to get event creator: %w", err)
+		logrus.Infof("Error adding invites: %s", err)
+		return 0, err
 	}
+	return added, nil
+}
 
-	// Формируем сообщение
-	text := fmt.Sprintf(
-		"🆕 Новый запрос на участие!\n\nСобытие: *%s*\nОт пользователя: @%s\n\nПринять или отклонить?",
-		event.Title, user.Username,
-	)
+// SendJoinDigests отправляет создателям событий с автоодобрением сводку о новых участниках
+// вместо уведомления на каждую заявку: одно сообщение на событие.
+func (s *EventService) SendJoinDigests() error {
+	entries, err := s.repo.ClaimJoinDigest()
+	if err != nil {
+		logrus.Infof("Error claiming join digest: %s", err)
+		return err
+	}
 
-	buttons := &telego.InlineKeyboardMarkup{
-		InlineKeyboard: [][]telego.InlineKeyboardButton{
-			{
-				{Text: "✅ Принять", CallbackData: fmt.Sprintf("approve_%d_%d", eventID, chatID)},
-				{Text: "❌ Отклонить", CallbackData: fmt.Sprintf("reject_%d_%d", eventID, chatID)},
-			},
-		},
+	type digest struct {
+		Title         string
+		CreatorChatID int64
+		Joined        []string
+		Waitlisted    []string
+	}
+	var order []int64
+	digests := make(map[int64]*digest)
+	for _, e := range entries {
+		d, ok := digests[e.EventID]
+		if !ok {
+			d = &digest{Title: e.EventTitle, CreatorChatID: e.CreatorChatID}
+			digests[e.EventID] = d
+			order = append(order, e.EventID)
+		}
+		if e.Status == models.ParticipantWaitlisted {
+			d.Waitlisted = append(d.Waitlisted, models.DisplayName(e.Username, e.ChatID))
+		} else {
+			d.Joined = append(d.Joined, models.DisplayName(e.Username, e.ChatID))
+		}
 	}
 
-	// Отправляем владельцу события уведомление
-	if s.bot != nil {
-		_, err = s.bot.SendMessage(
-			context.Background(),
-			&telego.SendMessageParams{
-				ChatID:      telego.ChatID{ID: creator.ChatID},
-				Text:        text,
-				ParseMode:   "Markdown",
-				ReplyMarkup: buttons,
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("failed to send Telegram message: %w", err)
+	ctx := context.Background()
+	for _, eventID := range order {
+		d := digests[eventID]
+		if err := s.notifier.SendTemplate(ctx, d.CreatorChatID, app.TemplateJoinDigest, d, nil); err != nil {
+			logrus.Errorf("Error sending join digest for event %d: %s", eventID, err)
 		}
 	}
+	if len(order) > 0 {
+		logrus.Infof("Sent join digests for %d events", len(order))
+	}
+	return nil
+}
+
+// ApproveRequest одобряет заявку; при исчерпанном лимите участник попадает в лист ожидания.
+// Возвращает итоговый статус заявки.
+func (s *EventService) ApproveRequest(eventID, creatorChatID, participantChatID int64) (string, error) {
+	status, err := s.repo.ApproveRequest(eventID, creatorChatID, participantChatID)
+	if err != nil {
+		logrus.Infof("Error approving request: %s", err)
+		return "", err
+	}
+	tmpl := app.TemplateRequestApproved
+	if status == models.ParticipantWaitlisted {
+		tmpl = app.TemplateRequestWaitlisted
+	}
+	s.notifyParticipant(eventID, participantChatID, tmpl)
+	return status, nil
+}
+
+func (s *EventService) RejectRequest(eventID, creatorChatID, participantChatID int64) error {
+	if err := s.repo.RejectRequest(eventID, creatorChatID, participantChatID); err != nil {
+		logrus.Infof("Error rejecting request: %s", err)
+		return err
+	}
+	s.notifyParticipant(eventID, participantChatID, app.TemplateRequestRejected)
+	return nil
+}
+
+// GetUserRequests — заявки пользователя на предстоящие события
+func (s *EventService) GetUserRequests(chatID int64) ([]models.UserRequest, error) {
+	requests, err := s.repo.GetUserRequests(chatID)
+	if err != nil {
+		logrus.Infof("Error getting user requests: %s", err)
+		return nil, err
+	}
+	return requests, nil
+}
+
+// LeaveEvent отзывает заявку или участие пользователя chatID и уведомляет создателя.
+// Если уходит одобренный участник, освободившееся место занимает первый из листа ожидания.
+func (s *EventService) LeaveEvent(eventID, chatID int64) error {
+	status, err := s.repo.LeaveEvent(eventID, chatID)
+	if err != nil {
+		logrus.Infof("Error leaving event: %s", err)
+		return err
+	}
+	if status == models.ParticipantApproved {
+		// Место отдаём листу ожидания, даже если уведомить создателя не получится
+		defer s.promoteWaitlisted(eventID)
+	}
 
+	// Заявка уже удалена, поэтому ошибки ниже не возвращаем: без данных просто не уведомляем создателя
+	event, err := s.repo.GetByID(eventID)
+	if err != nil {
+		logrus.Errorf("Error getting event %d, creator not notified: %s", eventID, err)
+		return nil
+	}
+	user, err := s.repAuth.GetUserById(chatID)
+	if err != nil {
+		logrus.Errorf("Error getting user %d, creator not notified: %s", chatID, err)
+		return nil
+	}
+	tmpl := app.TemplateRequestWithdrawn
+	if status == models.ParticipantApproved {
+		tmpl = app.TemplateParticipantLeft
+	}
+	data := map[string]any{"Event": event, "User": user}
+	if err := s.notifier.SendTemplate(context.Background(), event.CreatorTgID, tmpl, data, nil); err != nil {
+		logrus.Errorf("Error notifying event creator: %s", err)
+	}
 	return nil
 }
+
+// RemoveParticipant исключает участника по решению создателя и освобождает место для листа ожидания
+func (s *EventService) RemoveParticipant(eventID, creatorChatID, participantChatID int64) error {
+	status, err := s.repo.RemoveParticipant(eventID, creatorChatID, participantChatID)
+	if err != nil {
+		logrus.Infof("Error removing participant: %s", err)
+		return err
+	}
+	s.notifyParticipant(eventID, participantChatID, app.TemplateParticipantRemoved)
+	if status == models.ParticipantApproved {
+		s.promoteWaitlisted(eventID)
+	}
+	return nil
+}
+
+// promoteWaitlisted занимает свободные места участниками из листа ожидания и уведомляет их.
+// Ошибки только логируются: основное действие к этому моменту уже сохранено.
+func (s *EventService) promoteWaitlisted(eventID int64) {
+	promoted, err := s.repo.PromoteWaitlisted(eventID)
+	if err != nil {
+		logrus.Errorf("Error promoting waitlist of event %d: %s", eventID, err)
+		return
+	}
+	for _, p := range promoted {
+		s.notifyParticipant(eventID, p.ChatID, app.TemplateWaitlistPromoted)
+	}
+}
+
+// ParticipantsPageSize — сколько участников показывать создателю на одной странице
+const ParticipantsPageSize = 5
+
+// participantListStatuses — какие заявки видит создатель в списке участников, в порядке вывода
+var participantListStatuses = []string{models.ParticipantPending, models.ParticipantApproved, models.ParticipantWaitlisted}
+
+// GetEventParticipants — страница заявок события для его создателя
+func (s *EventService) GetEventParticipants(eventID, creatorChatID int64, page int) (models.ParticipantPage, error) {
+	event, err := s.repo.GetByID(eventID)
+	if err != nil {
+		logrus.Infof("Error getting event: %s", err)
+		return models.ParticipantPage{}, err
+	}
+	if event.CreatorTgID != creatorChatID {
+		return models.ParticipantPage{}, repository.ErrNotEventOwner
+	}
+
+	counts, err := s.repo.CountParticipants(eventID)
+	if err != nil {
+		logrus.Infof("Error counting participants: %s", err)
+		return models.ParticipantPage{}, err
+	}
+	result := models.ParticipantPage{
+		PageSize:   ParticipantsPageSize,
+		Pending:    counts[models.ParticipantPending],
+		Approved:   counts[models.ParticipantApproved],
+		Waitlisted: counts[models.ParticipantWaitlisted],
+	}
+	result.Total = result.Pending + result.Approved + result.Waitlisted
+	// Если после решений страница опустела, показываем последнюю
+	result.Page = min(max(page, 0), result.Pages()-1)
+
+	result.Participants, err = s.repo.GetParticipantsPage(eventID, participantListStatuses, ParticipantsPageSize, result.Page*ParticipantsPageSize)
+	if err != nil {
+		logrus.Infof("Error getting participants: %s", err)
+		return models.ParticipantPage{}, err
+	}
+	return result, nil
+}
+
+// ApproveAllPending одобряет все ожидающие заявки события; не поместившиеся в лимит
+// попадают в лист ожидания. Возвращает, сколько заявок одобрено и сколько ушло в лист ожидания.
+func (s *EventService) ApproveAllPending(eventID, creatorChatID int64) (approved, waitlisted int, err error) {
+	decided, err := s.repo.ApproveAllPending(eventID, creatorChatID)
+	if err != nil {
+		logrus.Infof("Error approving pending requests: %s", err)
+		return 0, 0, err
+	}
+	for _, p := range decided {
+		tmpl := app.TemplateRequestApproved
+		if p.Status == models.ParticipantWaitlisted {
+			tmpl = app.TemplateRequestWaitlisted
+			waitlisted++
+		} else {
+			approved++
+		}
+		s.notifyParticipant(eventID, p.ChatID, tmpl)
+	}
+	return approved, waitlisted, nil
+}
+
+// notifyParticipant сообщает участнику о решении по заявке; ошибки только логируются,
+// т.к. само решение уже сохранено
+func (s *EventService) notifyParticipant(eventID, participantChatID int64, tmpl string) {
+	event, err := s.repo.GetByID(eventID)
+	if err != nil {
+		logrus.Infof("Error getting event: %s", err)
+		return
+	}
+	data := map[string]any{"Event": event}
+	if err := s.notifier.SendTemplate(context.Background(), participantChatID, tmpl, data, nil); err != nil {
+		logrus.Errorf("Error notifying participant: %s", err)
+	}
+}
+
 func (s *EventService) GetByID(id int64) (models.Event, error) {
 	return s.repo.GetByID(id)
 }
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"tg-bot/internal/app"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+)
+
+// eventsRepoStub реализует только нужные тесту методы repository.Events
+type eventsRepoStub struct {
+	repository.Events
+	digest       []models.JoinDigestEntry
+	finished     []models.Event
+	participants map[int64][]models.Participant
+}
+
+func (r *eventsRepoStub) FinishExpiredEvents() ([]models.Event, error) {
+	return r.finished, nil
+}
+
+func (r *eventsRepoStub) GetParticipants(eventID int64, _ ...string) ([]models.Participant, error) {
+	return r.participants[eventID], nil
+}
+
+func (r *eventsRepoStub) ClaimJoinDigest() ([]models.JoinDigestEntry, error) {
+	return r.digest, nil
+}
+
+func TestSendJoinDigests(t *testing.T) {
+	repo := &eventsRepoStub{digest: []models.JoinDigestEntry{
+		{EventID: 1, EventTitle: "Кино", CreatorChatID: 100, ChatID: 7, Username: "alice", Status: models.ParticipantApproved},
+		{EventID: 1, EventTitle: "Кино", CreatorChatID: 100, ChatID: 8, Status: models.ParticipantApproved},
+		{EventID: 1, EventTitle: "Кино", CreatorChatID: 100, ChatID: 9, Status: models.ParticipantWaitlisted},
+		{EventID: 2, EventTitle: "Театр", CreatorChatID: 200, ChatID: 7, Username: "alice", Status: models.ParticipantApproved},
+	}}
+	notifier := app.NewFakeNotifier()
+	if err := NewEventService(repo, nil, nil, notifier).SendJoinDigests(); err != nil {
+		t.Fatalf("SendJoinDigests: %v", err)
+	}
+
+	got := notifier.MessagesTo(100)
+	if len(got) != 1 {
+		t.Fatalf("creator 100 got %d digests, want 1", len(got))
+	}
+	text := got[0].Text
+	for _, want := range []string{"«Кино»", "• @alice", "• id8", "В листе ожидания:\n• id9"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("%q does not contain %q", text, want)
+		}
+	}
+	if strings.Contains(text, "• @\n") || strings.HasSuffix(text, "• @") {
+		t.Errorf("bare @ in digest: %q", text)
+	}
+	if len(notifier.MessagesTo(200)) != 1 {
+		t.Fatalf("creator 200 got %+v", notifier.MessagesTo(200))
+	}
+}
+
+func TestCheckAndUpdateEvents(t *testing.T) {
+	repo := &eventsRepoStub{
+		finished: []models.Event{
+			// Набор закрыт создателем заранее — участников всё равно благодарим
+			{ID: 1, Title: "Кино", CreatorTgID: 100, Status: models.EventClosed},
+			// Черновик так и не опубликовали — итог создателю не нужен
+			{ID: 2, Title: "Черновик", CreatorTgID: 200, Status: models.EventDraft},
+		},
+		participants: map[int64][]models.Participant{
+			1: {{ChatID: 7}, {ChatID: 8}},
+		},
+	}
+	notifier := app.NewFakeNotifier()
+	if err := NewEventService(repo, nil, nil, notifier).CheckAndUpdateEvents(); err != nil {
+		t.Fatalf("CheckAndUpdateEvents: %v", err)
+	}
+
+	for _, chatID := range []int64{7, 8} {
+		if got := notifier.MessagesTo(chatID); len(got) != 1 || !strings.Contains(got[0].Text, "Спасибо") {
+			t.Errorf("participant %d got %+v", chatID, got)
+		}
+	}
+	if got := notifier.MessagesTo(100); len(got) != 1 || !strings.Contains(got[0].Text, "Участников: 2") {
+		t.Errorf("creator got %+v", got)
+	}
+	if got := notifier.MessagesTo(200); len(got) != 0 {
+		t.Errorf("draft creator got %+v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"tg-bot/internal/adapters/rabbitmq"
+	"tg-bot/internal/repository"
+	"time"
+)
+
+const (
+	// outboxLease — сколько сообщение считается «в работе» у relay, прежде чем его смогут забрать снова
+	outboxLease = time.Minute
+	// outboxMaxBackoff — верхняя граница задержки между повторными попытками отправки
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+type OutboxService struct {
+	repo   repository.Outbox
+	broker *rabbitmq.RabbitMQ
+}
+
+func NewOutboxService(repo repository.Outbox, rmq *rabbitmq.RabbitMQ) *OutboxService {
+	return &OutboxService{repo: repo, broker: rmq}
+}
+
+// Relay пересылает до limit накопленных доменных событий в RabbitMQ: в обменник сообщения
+// с типом события в качестве ключа маршрутизации.
+// Отправленные помечаются sent_at; при ошибке попытка повторяется с экспоненциальной задержкой.
+// Возвращает количество успешно отправленных сообщений.
+func (s *OutboxService) Relay(limit int) (int, error) {
+	messages, err := s.repo.ClaimPending(limit, outboxLease)
+	if err != nil {
+		logrus.Errorf("Error claiming outbox: %s", err)
+		return 0, err
+	}
+
+	sent := 0
+	for _, msg := range messages {
+		if err := s.broker.Publish(msg.Exchange, msg.EventType, msg.Payload); err != nil {
+			retryIn := outboxRetryDelay(msg.Attempts)
+			logrus.Warnf("outbox: publish %s #%d failed (attempt %d), retry in %s: %s",
+				msg.EventType, msg.ID, msg.Attempts+1, retryIn, err)
+			if err := s.repo.MarkFailed(msg.ID, err.Error(), retryIn); err != nil {
+				logrus.Errorf("Error marking outbox #%d failed: %s", msg.ID, err)
+			}
+			continue
+		}
+		if err := s.repo.MarkSent(msg.ID); err != nil {
+			// Сообщение уйдёт повторно после lease — потребитель должен это переживать
+			logrus.Errorf("Error marking outbox #%d sent: %s", msg.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Cleanup удаляет отправленные сообщения старше retention
+func (s *OutboxService) Cleanup(retention time.Duration) error {
+	if err := s.repo.DeleteSent(retention); err != nil {
+		logrus.Errorf("Error cleaning outbox: %s", err)
+		return err
+	}
+	return nil
+}
+
+// outboxRetryDelay — 1s, 2s, 4s… по числу прошлых попыток, но не больше outboxMaxBackoff
+func outboxRetryDelay(attempts int) time.Duration {
+	if attempts >= 9 {
+		return outboxMaxBackoff
+	}
+	return min(time.Second<<attempts, outboxMaxBackoff)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"slices"
+	"tg-bot/internal/app"
+	"tg-bot/internal/models"
+	"tg-bot/internal/repository"
+	"time"
+)
+
+type ReminderService struct {
+	repo     repository.Reminders
+	notifier app.Notifier
+}
+
+func NewReminderService(repo repository.Reminders, notifier app.Notifier) *ReminderService {
+	return &ReminderService{repo: repo, notifier: notifier}
+}
+
+// SendDueReminders рассылает напоминания для всех отступов (например, 24h и 1h до начала).
+// Каждый отступ отвечает за окно до следующего меньшего, поэтому участник не получит
+// два напоминания подряд, если событие создано незадолго до начала.
+func (s *ReminderService) SendDueReminders(offsets []time.Duration) error {
+	sorted := slices.Clone(offsets)
+	slices.Sort(sorted)
+
+	ctx := context.Background()
+	for i, offset := range sorted {
+		var lower time.Duration
+		if i > 0 {
+			lower = sorted[i-1]
+		}
+		reminders, err := s.repo.GetDueReminders(offset, lower)
+		if err != nil {
+			logrus.Infof("Error getting due reminders: %s", err)
+			return err
+		}
+		for _, rem := range reminders {
+			// Сначала фиксируем отправку, чтобы после рестарта или на другой реплике не было дублей
+			claimed, err := s.repo.MarkSent(rem.EventID, rem.UserID, offset)
+			if err != nil {
+				logrus.Errorf("Error marking reminder as sent: %s", err)
+				continue
+			}
+			if !claimed {
+				continue
+			}
+			buttons := [][]app.Button{{{Text: "🔕 Не напоминать", CallbackData: fmt.Sprintf("remind_off_%d", rem.EventID)}}}
+			data := map[string]any{
+				"Reminder": rem,
+				"Date":     rem.Date.In(models.LoadLocation(rem.Timezone)),
+				"Left":     formatLeft(time.Until(rem.Date)),
+			}
+			if err := s.notifier.SendTemplate(ctx, rem.ChatID, app.TemplateEventReminder, data, buttons); err != nil {
+				logrus.Errorf("Error sending reminder to %d: %s", rem.ChatID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ReminderService) SetReminders(eventID, chatID int64, enabled bool) error {
+	if err := s.repo.SetEnabled(eventID, chatID, enabled); err != nil {
+		logrus.Infof("Error updating reminders: %s", err)
+		return err
+	}
+	return nil
+}
+
+// formatLeft — «1 ч 30 мин» до начала события
+func formatLeft(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d мин", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d ч", hours)
+	default:
+		return fmt.Sprintf("%d ч %d мин", hours, minutes)
+	}
+}
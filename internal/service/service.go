@@ -1,38 +1,93 @@
 package service
 
 import (
+	"errors"
+	amqp "github.com/rabbitmq/amqp091-go"
 	"tg-bot/internal/adapters/rabbitmq"
+	"tg-bot/internal/app"
 	"tg-bot/internal/models"
 	"tg-bot/internal/repository"
+	"time"
+)
+
+var (
+	ErrInvalidEvent    = errors.New("invalid event")
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrInvalidUsername = errors.New("invalid telegram username")
 )
 
 type Auth interface {
 	Create(user models.User) (int64, error)
 	GetUserById(id int64) (models.User, error)
+	SetTimezone(chatID int64, timezone string) (*time.Location, error)
+	GetLocation(chatID int64) *time.Location
 }
 type Events interface {
 	Create(event models.Event, chatID int64) (int64, error)
-	GetEvents() ([]models.Event, error)
+	GetEvents(page int) (models.EventPage, error)
+	GetEventsInRange(from, to time.Time, page int) (models.EventPage, error)
+	GetEventsByCategory(categoryID int64, page int) (models.EventPage, error)
+	GetEventsNear(lat, lon, radiusKm float64) ([]models.NearbyEvent, error)
 	GetMyEvents(chatID int64) ([]models.Event, error)
 	DeleteEvent(eventID, chatID int64) error
 	SearchEvents(query string) ([]models.Event, error)
 	SearchEventRandom() (models.Event, error)
-	RequestJoin(eventID, chatID int64) error
+	RequestJoin(eventID, chatID int64) (string, error)
+	AddInvites(eventID, creatorChatID int64, usernames []string) (int, error)
+	SendJoinDigests() error
+	UpdateEvent(edited models.Event, field string, chatID int64) (models.Event, error)
+	PublishEvent(eventID, chatID int64) error
+	CloseEvent(eventID, chatID int64) error
+	CheckAndUpdateEvents() error
+	ApproveRequest(eventID, creatorChatID, participantChatID int64) (string, error)
+	RejectRequest(eventID, creatorChatID, participantChatID int64) error
+	GetUserRequests(chatID int64) ([]models.UserRequest, error)
+	LeaveEvent(eventID, chatID int64) error
+	RemoveParticipant(eventID, creatorChatID, participantChatID int64) error
+	GetEventParticipants(eventID, creatorChatID int64, page int) (models.ParticipantPage, error)
+	ApproveAllPending(eventID, creatorChatID int64) (approved, waitlisted int, err error)
 	GetByID(id int64) (models.Event, error)
 }
 type Stats interface {
 	HandleEvent(body []byte) error
 }
+type Categories interface {
+	GetCategories() ([]models.Category, error)
+	GetCategory(id int64) (models.Category, error)
+	FindCategory(title string) (models.Category, error)
+}
+type Reminders interface {
+	SendDueReminders(offsets []time.Duration) error
+	SetReminders(eventID, chatID int64, enabled bool) error
+}
+type Outbox interface {
+	Relay(limit int) (int, error)
+	Cleanup(retention time.Duration) error
+}
+type DeadLetters interface {
+	Setup(queue string) error
+	Retry(queue string, msg amqp.Delivery, cause error) error
+	List(limit int) ([]models.DeadLetter, int, error)
+	Replay(limit int) (int, error)
+}
 type Service struct {
 	Auth
 	Stats
 	Events
+	Categories
+	Reminders
+	Outbox
+	DeadLetters
 }
 
-func NewService(rep *repository.Repository, rmq *rabbitmq.RabbitMQ) *Service {
+func NewService(rep *repository.Repository, rmq *rabbitmq.RabbitMQ, notifier app.Notifier, retry RetryPolicy) *Service {
 	return &Service{
-		Auth:   NewAuthService(rep.Auth, rmq),
-		Stats:  NewStatsService(rep.Stats),
-		Events: NewEventService(rep.Events, rep.Auth, rmq),
+		Auth:        NewAuthService(rep.Auth),
+		Stats:       NewStatsService(rep.Stats),
+		Events:      NewEventService(rep.Events, rep.Auth, rmq, notifier),
+		Categories:  NewCategoryService(rep.Categories),
+		Reminders:   NewReminderService(rep.Reminders, notifier),
+		Outbox:      NewOutboxService(rep.Outbox, rmq),
+		DeadLetters: NewDeadLetterService(rmq, retry),
 	}
 }
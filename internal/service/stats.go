@@ -1,7 +1,7 @@
 package service
 
 import (
-	"encoding/json"
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"tg-bot/internal/models"
 	"tg-bot/internal/repository"
@@ -15,23 +15,59 @@ func NewStatsService(repo repository.Stats) *StatsService {
 	return &StatsService{repo: repo}
 }
 
+// HandleEvent разбирает конверт доменного события и передаёт его обработчику своего типа.
+// Сообщения, не прошедшие проверку схемы, возвращают ошибку с models.ErrInvalidDomainEvent:
+// повторять их обработку бессмысленно.
 func (s *StatsService) HandleEvent(body []byte) error {
-	var event map[string]interface{}
-	if err := json.Unmarshal(body, &event); err != nil {
-		logrus.Errorf("failed to unmarshal event: %s", err)
+	env, event, err := models.DecodeEnvelope(body)
+	if err != nil {
+		logrus.Errorf("failed to decode event: %s", err)
 		return err
 	}
 
-	// Пример: сохраняем в БД
+	switch e := event.(type) {
+	case *models.UserCreated:
+		return s.onUserCreated(env, e)
+	case *models.EventCreated:
+		return s.onEventCreated(env, e)
+	case *models.JoinRequested:
+		return s.onJoinRequested(env, e)
+	case *models.JoinApproved:
+		return s.onJoinApproved(env, e)
+	}
+	return fmt.Errorf("no stats handler for %s: %w", env.Type, models.ErrInvalidDomainEvent)
+}
+
+func (s *StatsService) onUserCreated(env models.Envelope, e *models.UserCreated) error {
+	logrus.Infof("stats: user %d registered (chat %d)", e.UserID, e.ChatID)
+	return s.save(env)
+}
+
+func (s *StatsService) onEventCreated(env models.Envelope, e *models.EventCreated) error {
+	logrus.Infof("stats: event %d created by chat %d (policy %s)", e.EventID, env.ActorChatID, e.JoinPolicy)
+	return s.save(env)
+}
+
+func (s *StatsService) onJoinRequested(env models.Envelope, e *models.JoinRequested) error {
+	logrus.Infof("stats: chat %d requested event %d (%s)", e.ChatID, e.EventID, e.Status)
+	return s.save(env)
+}
+
+func (s *StatsService) onJoinApproved(env models.Envelope, e *models.JoinApproved) error {
+	logrus.Infof("stats: chat %d approved for event %d", e.ChatID, e.EventID)
+	return s.save(env)
+}
+
+// save пишет событие в статистику; повторная доставка того же конверта не создаёт дубль
+func (s *StatsService) save(env models.Envelope) error {
 	stat := models.Statistic{
-		Event: event["event"].(string),
-		Data:  string(body),
+		EventID: env.ID,
+		Event:   env.Type,
+		Data:    string(env.Payload),
 	}
 	if err := s.repo.Save(stat); err != nil {
 		logrus.Errorf("failed to save statistic: %s", err)
 		return err
 	}
-
-	logrus.Infof("Stat saved: %+v", stat)
 	return nil
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"tg-bot/internal/models"
+)
+
+type statsRepoStub struct {
+	saved []models.Statistic
+}
+
+func (r *statsRepoStub) Save(stat models.Statistic) error {
+	r.saved = append(r.saved, stat)
+	return nil
+}
+
+func TestStatsHandleEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		invalid bool
+	}{
+		{"envelope", `{"id":"e1","type":"user.created","version":1,"occurred_at":"2025-11-15T10:00:00Z",` +
+			`"payload":{"user_id":1,"chat_id":2,"username":"alice"}}`, false},
+		// Сообщения из старого формата раньше роняли потребителя на type assertion
+		{"legacy map format", `{"event":"user_created","user":1,"chat_id":2}`, true},
+		{"legacy map without event", `{"user":1}`, true},
+		{"not json", `{`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &statsRepoStub{}
+			err := NewStatsService(repo).HandleEvent([]byte(tt.body))
+			if tt.invalid {
+				if !errors.Is(err, models.ErrInvalidDomainEvent) {
+					t.Fatalf("want ErrInvalidDomainEvent, got %v", err)
+				}
+				if len(repo.saved) != 0 {
+					t.Fatalf("invalid message saved: %+v", repo.saved)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(repo.saved) != 1 || repo.saved[0].EventID != "e1" || repo.saved[0].Event != models.DomainUserCreated {
+				t.Fatalf("saved %+v", repo.saved)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+-- Координаты места события (WGS84); NULL — место указано только текстом
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+-- Грубый отбор по широте перед точным расчётом расстояния
+CREATE INDEX IF NOT EXISTS idx_events_geo ON events (latitude, longitude)
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Лимит участников события; NULL — без ограничения
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS max_participants INT CHECK (max_participants > 0);
+
+-- Очередь листа ожидания и подсчёт одобренных участников по событию
+CREATE INDEX IF NOT EXISTS idx_event_participants_event_status
+    ON event_participants (event_id, status, requested_at);
@@ -0,0 +1,17 @@
+-- Политика вступления: manual — создатель подтверждает каждую заявку,
+-- auto — заявки одобряются сразу, invite — только для приглашённых
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS join_policy VARCHAR(20) NOT NULL DEFAULT 'manual'
+        CHECK (join_policy IN ('manual', 'auto', 'invite'));
+
+-- Приглашения в событие по username (в нижнем регистре, без @)
+CREATE TABLE IF NOT EXISTS event_invites (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    username TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, username)
+    );
+
+-- Когда участник автоматически одобренного события попал в сводку для создателя
+ALTER TABLE event_participants
+    ADD COLUMN IF NOT EXISTS digested_at TIMESTAMPTZ;
@@ -0,0 +1,57 @@
+-- ID конверта доменного события: повторная доставка того же сообщения не дублирует статистику
+ALTER TABLE statistics
+    ADD COLUMN IF NOT EXISTS event_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statistics_event_id ON statistics (event_id);
+
+-- Неотправленные сообщения outbox старого формата ({"event": "<тип>", ...}) перепаковываются
+-- в конверт версии 1: иначе потребитель сочтёт их невалидными и отправит в DLQ.
+-- ID конверта стабилен ("outbox-<id>"), чтобы повторная отправка не задвоила статистику.
+UPDATE outbox o
+SET event_type = c.type,
+    payload = jsonb_build_object(
+        'id', 'outbox-' || o.id,
+        'type', c.type,
+        'version', 1,
+        'occurred_at', to_jsonb(o.created_at),
+        'actor_chat_id', c.actor_chat_id,
+        'payload', c.payload
+    )
+FROM (
+    SELECT id,
+           CASE event_type
+               WHEN 'user_created' THEN 'user.created'
+               WHEN 'event_created' THEN 'event.created'
+               WHEN 'join_requested' THEN 'participant.requested'
+               WHEN 'join_approved' THEN 'participant.approved'
+           END AS type,
+           -- Одобрение мог сделать создатель или система — исполнитель в старом формате не хранился
+           CASE WHEN event_type = 'join_approved' THEN 0 ELSE COALESCE((payload->>'chat_id')::BIGINT, 0) END AS actor_chat_id,
+           CASE event_type
+               WHEN 'user_created' THEN jsonb_build_object(
+                   'user_id', (payload->>'user')::BIGINT,
+                   'chat_id', (payload->>'chat_id')::BIGINT,
+                   'username', COALESCE((SELECT u.username FROM users u WHERE u.id = (payload->>'user')::BIGINT), ''))
+               WHEN 'event_created' THEN jsonb_build_object(
+                   'event_id', (payload->>'event_id')::BIGINT,
+                   'title', COALESCE((SELECT e.title FROM events e WHERE e.id = (payload->>'event_id')::BIGINT), ''),
+                   'category_id', payload->'category_id',
+                   'join_policy', COALESCE((SELECT e.join_policy FROM events e WHERE e.id = (payload->>'event_id')::BIGINT), 'manual'))
+               WHEN 'join_requested' THEN jsonb_build_object(
+                   'event_id', (payload->>'event_id')::BIGINT,
+                   'chat_id', (payload->>'chat_id')::BIGINT,
+                   'status', payload->>'status')
+               ELSE jsonb_build_object(
+                   'event_id', (payload->>'event_id')::BIGINT,
+                   'chat_id', (payload->>'chat_id')::BIGINT)
+           END AS payload
+    FROM outbox
+    WHERE sent_at IS NULL
+      AND event_type IN ('user_created', 'event_created', 'join_requested', 'join_approved')
+) c
+WHERE o.id = c.id;
+
+-- Всё, что перепаковать не удалось, — неизвестные типы старого формата — удаляем
+DELETE FROM outbox
+WHERE sent_at IS NULL
+  AND event_type NOT IN ('user.created', 'event.created', 'participant.requested', 'participant.approved');
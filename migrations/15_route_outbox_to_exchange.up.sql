@@ -0,0 +1,6 @@
+-- Доменные события публикуются в topic-обменник с ключом маршрутизации = типу события,
+-- а не напрямую в очередь user.events
+ALTER TABLE outbox
+    RENAME COLUMN queue TO exchange;
+
+UPDATE outbox SET exchange = 'kaidabaram.events' WHERE sent_at IS NULL;
@@ -0,0 +1,9 @@
+-- Момент, когда прошедшее событие обработано cron-ом (благодарности и итог отправлены).
+-- Не совпадает со статусом closed: создатель может закрыть набор заранее через /close_<id>
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS finished_at TIMESTAMPTZ;
+
+-- Уже прошедшие события считаем обработанными, чтобы не разослать итоги задним числом
+UPDATE events SET finished_at = NOW() WHERE date < NOW() AND finished_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_unfinished ON events (date) WHERE finished_at IS NULL;
@@ -0,0 +1,11 @@
+ALTER TABLE event_participants
+    ADD COLUMN IF NOT EXISTS reminders_enabled BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS event_reminders (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offset_minutes INT NOT NULL, -- за сколько минут до начала события
+    sent_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (event_id, user_id, offset_minutes)
+    );
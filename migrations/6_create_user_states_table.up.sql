@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_states (
+    chat_id BIGINT PRIMARY KEY,
+    state JSONB NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT NOW()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_user_states_expires_at ON user_states (expires_at);
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id SERIAL PRIMARY KEY,
+    title TEXT NOT NULL UNIQUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW()
+    );
+
+INSERT INTO categories (title, sort_order) VALUES
+    ('Концерты', 10),
+    ('Спорт', 20),
+    ('Образование', 30),
+    ('Нетворкинг', 40),
+    ('Выставки', 50),
+    ('Кино и театр', 60),
+    ('Вечеринки', 70),
+    ('Другое', 1000)
+ON CONFLICT (title) DO NOTHING;
+
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS category_id INT REFERENCES categories(id) ON DELETE SET NULL;
+
+-- Привязываем уже созданные события к каталогу по совпадению названия категории
+UPDATE events e
+SET category_id = c.id
+FROM categories c
+WHERE e.category_id IS NULL AND LOWER(TRIM(e.category)) = LOWER(c.title);
+
+CREATE INDEX IF NOT EXISTS idx_events_category_id ON events (category_id);
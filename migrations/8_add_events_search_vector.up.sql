@@ -0,0 +1,12 @@
+-- Полнотекстовый поиск: русская морфология для названия, категории и описания,
+-- simple-конфиг для названия и места (имена собственные, адреса, латиница)
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(title, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(category, '')), 'B') ||
+        setweight(to_tsvector('simple', COALESCE(location, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(description, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_events_search_vector ON events USING GIN (search_vector);